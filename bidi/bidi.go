@@ -0,0 +1,722 @@
+// Package bidi implements the Unicode Bidirectional Algorithm (UAX #9):
+// paragraph direction detection, explicit embeddings and isolates, resolution
+// of weak and neutral types including paired brackets, and per-line visual
+// reordering.
+//
+// A Paragraph holds the resolved embedding levels of a single paragraph of
+// text; callers split text at paragraph separators (such as '\n') first.
+// Line breaking happens between resolution and reordering: once a paragraph
+// is broken into lines, Reorder and Runs give the visual order of each line.
+package bidi
+
+import (
+  "sort"
+
+  xbidi "golang.org/x/text/unicode/bidi"
+)
+
+// Direction is a paragraph or run direction.
+type Direction int
+
+const (
+  // Auto picks the paragraph direction from its first strong character,
+  // defaulting to left-to-right.
+  Auto Direction = iota
+  LeftToRight
+  RightToLeft
+)
+
+// maxDepth is the maximum explicit embedding level (BD2).
+const maxDepth = 125
+
+// Paragraph is a paragraph of text with resolved embedding levels.
+type Paragraph struct {
+  runes   []rune
+  classes []xbidi.Class
+  level   uint8
+  levels  []uint8
+}
+
+// Run is a maximal range of runes [Start, End) sharing one embedding level.
+type Run struct {
+  Start, End int
+  Level      uint8
+}
+
+// Direction returns the direction of the run.
+func (r Run) Direction() Direction {
+  if r.Level&1 == 1 {
+    return RightToLeft
+  }
+  return LeftToRight
+}
+
+func classOf(r rune) xbidi.Class {
+  p, _ := xbidi.LookupRune(r)
+  return p.Class()
+}
+
+// NewParagraph resolves the embedding levels of a paragraph of runes.
+func NewParagraph(runes []rune, dir Direction) *Paragraph {
+  p := &Paragraph{
+    runes:   runes,
+    classes: make([]xbidi.Class, len(runes)),
+  }
+  for i, r := range runes {
+    p.classes[i] = classOf(r)
+  }
+
+  switch dir {
+  case LeftToRight:
+    p.level = 0
+  case RightToLeft:
+    p.level = 1
+  default:
+    if p.firstStrong(0, len(runes)) == xbidi.R {
+      p.level = 1
+    }
+  }
+
+  p.resolve()
+  return p
+}
+
+// DetectDirection returns the direction of the first strong character of
+// runes (rules P2 and P3), or Auto when there is none.
+func DetectDirection(runes []rune) Direction {
+  p := &Paragraph{runes: runes, classes: make([]xbidi.Class, len(runes))}
+  for i, r := range runes {
+    p.classes[i] = classOf(r)
+  }
+  switch p.firstStrong(0, len(runes)) {
+  case xbidi.L:
+    return LeftToRight
+  case xbidi.R:
+    return RightToLeft
+  }
+  return Auto
+}
+
+// Level returns the paragraph embedding level.
+func (p *Paragraph) Level() uint8 {
+  return p.level
+}
+
+// Direction returns the paragraph direction.
+func (p *Paragraph) Direction() Direction {
+  if p.level&1 == 1 {
+    return RightToLeft
+  }
+  return LeftToRight
+}
+
+// Levels returns the resolved embedding level of each rune, before the
+// per-line rules are applied.
+func (p *Paragraph) Levels() []uint8 {
+  return append([]uint8(nil), p.levels...)
+}
+
+// firstStrong returns L or R for the first strong character in [start, end),
+// skipping isolates, or ON when there is none.
+func (p *Paragraph) firstStrong(start, end int) xbidi.Class {
+  depth := 0
+  for i := start; i < end; i++ {
+    switch c := p.classes[i]; c {
+    case xbidi.L, xbidi.R, xbidi.AL:
+      if depth == 0 {
+        if c == xbidi.L {
+          return xbidi.L
+        }
+        return xbidi.R
+      }
+    case xbidi.LRI, xbidi.RLI, xbidi.FSI:
+      depth++
+    case xbidi.PDI:
+      if depth > 0 {
+        depth--
+      }
+    case xbidi.B:
+      return xbidi.ON
+    }
+  }
+  return xbidi.ON
+}
+
+// matchingPDI returns the index of the PDI closing the isolate initiator at
+// i, or len(runes) when it isn't closed (BD9).
+func (p *Paragraph) matchingPDI(i int) int {
+  depth := 0
+  for j := i + 1; j < len(p.classes); j++ {
+    switch p.classes[j] {
+    case xbidi.LRI, xbidi.RLI, xbidi.FSI:
+      depth++
+    case xbidi.PDI:
+      if depth == 0 {
+        return j
+      }
+      depth--
+    case xbidi.B:
+      return len(p.classes)
+    }
+  }
+  return len(p.classes)
+}
+
+func isIsolateInitiator(c xbidi.Class) bool {
+  return c == xbidi.LRI || c == xbidi.RLI || c == xbidi.FSI
+}
+
+// isRemoved reports whether a class is removed by rule X9.
+func isRemoved(c xbidi.Class) bool {
+  switch c {
+  case xbidi.LRE, xbidi.RLE, xbidi.LRO, xbidi.RLO, xbidi.PDF, xbidi.BN:
+    return true
+  }
+  return false
+}
+
+type status struct {
+  level    uint8
+  override xbidi.Class
+  isolate  bool
+}
+
+// resolve runs rules X1 to I2 over the paragraph.
+func (p *Paragraph) resolve() {
+  n := len(p.runes)
+  p.levels = make([]uint8, n)
+  types := append([]xbidi.Class(nil), p.classes...)
+
+  // X1-X8: explicit levels and directions
+  stack := []status{{level: p.level, override: xbidi.ON}}
+  overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+  for i := 0; i < n; i++ {
+    top := stack[len(stack)-1]
+    switch c := p.classes[i]; c {
+    case xbidi.RLE, xbidi.LRE, xbidi.RLO, xbidi.LRO, xbidi.RLI, xbidi.LRI, xbidi.FSI:
+      isolate := isIsolateInitiator(c)
+      rtl := c == xbidi.RLE || c == xbidi.RLO || c == xbidi.RLI
+      if c == xbidi.FSI {
+        rtl = p.firstStrong(i+1, p.matchingPDI(i)) == xbidi.R
+      }
+      p.levels[i] = top.level
+      if isolate && top.override != xbidi.ON {
+        types[i] = top.override
+      }
+      var level uint8
+      if rtl {
+        level = (top.level + 1) | 1
+      } else {
+        level = (top.level + 2) &^ 1
+      }
+      if level <= maxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+        if isolate {
+          validIsolates++
+        }
+        s := status{level: level, override: xbidi.ON, isolate: isolate}
+        switch c {
+        case xbidi.LRO:
+          s.override = xbidi.L
+        case xbidi.RLO:
+          s.override = xbidi.R
+        }
+        stack = append(stack, s)
+        if !isolate {
+          p.levels[i] = level
+        }
+      } else if isolate {
+        overflowIsolates++
+      } else if overflowIsolates == 0 {
+        overflowEmbeddings++
+      }
+
+    case xbidi.PDI:
+      if overflowIsolates > 0 {
+        overflowIsolates--
+      } else if validIsolates > 0 {
+        overflowEmbeddings = 0
+        for !stack[len(stack)-1].isolate {
+          stack = stack[:len(stack)-1]
+        }
+        stack = stack[:len(stack)-1]
+        validIsolates--
+      }
+      top = stack[len(stack)-1]
+      p.levels[i] = top.level
+      if top.override != xbidi.ON {
+        types[i] = top.override
+      }
+
+    case xbidi.PDF:
+      switch {
+      case overflowIsolates > 0:
+      case overflowEmbeddings > 0:
+        overflowEmbeddings--
+      case !top.isolate && len(stack) >= 2:
+        stack = stack[:len(stack)-1]
+      }
+      p.levels[i] = top.level
+
+    case xbidi.B:
+      p.levels[i] = p.level
+
+    default:
+      p.levels[i] = top.level
+      if top.override != xbidi.ON && c != xbidi.BN {
+        types[i] = top.override
+      }
+    }
+  }
+
+  // X9: removed characters take no part in the following rules
+  for i := range types {
+    if isRemoved(p.classes[i]) {
+      types[i] = xbidi.BN
+    }
+  }
+
+  // X10: resolve each isolating run sequence; sos and eos come from the
+  // explicit levels, before any sequence has been resolved
+  explicit := append([]uint8(nil), p.levels...)
+  for _, seq := range p.isolatingRunSequences(types) {
+    p.resolveSequence(seq, types, explicit)
+  }
+
+  // removed characters take the level of the preceding character
+  for i := range p.levels {
+    if types[i] == xbidi.BN {
+      if i == 0 {
+        p.levels[i] = p.level
+      } else {
+        p.levels[i] = p.levels[i-1]
+      }
+    }
+  }
+}
+
+// isolatingRunSequences returns the isolating run sequences of the paragraph
+// (BD13) as lists of rune indices, ignoring characters removed by X9.
+func (p *Paragraph) isolatingRunSequences(types []xbidi.Class) [][]int {
+  // level runs
+  var runs [][]int
+  var run []int
+  for i := range p.runes {
+    if types[i] == xbidi.BN {
+      continue
+    }
+    if len(run) > 0 && p.levels[run[0]] != p.levels[i] {
+      runs = append(runs, run)
+      run = nil
+    }
+    run = append(run, i)
+  }
+  if len(run) > 0 {
+    runs = append(runs, run)
+  }
+
+  // link runs ending in an isolate initiator with the run starting at its PDI
+  runOf := make(map[int]int)
+  for k, r := range runs {
+    runOf[r[0]] = k
+  }
+  matchedPDI := make(map[int]bool)
+  for i, c := range p.classes {
+    if isIsolateInitiator(c) {
+      if j := p.matchingPDI(i); j < len(p.classes) {
+        matchedPDI[j] = true
+      }
+    }
+  }
+
+  var seqs [][]int
+  for _, r := range runs {
+    if p.classes[r[0]] == xbidi.PDI && matchedPDI[r[0]] {
+      continue
+    }
+    seq := append([]int(nil), r...)
+    for {
+      last := seq[len(seq)-1]
+      if !isIsolateInitiator(p.classes[last]) {
+        break
+      }
+      j := p.matchingPDI(last)
+      k, ok := runOf[j]
+      if !ok {
+        break
+      }
+      seq = append(seq, runs[k]...)
+    }
+    seqs = append(seqs, seq)
+  }
+  return seqs
+}
+
+// strongOf maps a resolved type to L or R for the neutral rules, counting
+// numbers as R; other types give ON.
+func strongOf(c xbidi.Class) xbidi.Class {
+  switch c {
+  case xbidi.L:
+    return xbidi.L
+  case xbidi.R, xbidi.AL, xbidi.EN, xbidi.AN:
+    return xbidi.R
+  }
+  return xbidi.ON
+}
+
+func isNeutral(c xbidi.Class) bool {
+  switch c {
+  case xbidi.B, xbidi.S, xbidi.WS, xbidi.ON, xbidi.LRI, xbidi.RLI, xbidi.FSI, xbidi.PDI:
+    return true
+  }
+  return false
+}
+
+func directionOfLevel(level uint8) xbidi.Class {
+  if level&1 == 1 {
+    return xbidi.R
+  }
+  return xbidi.L
+}
+
+// resolveSequence runs rules W1 to I2 over one isolating run sequence.
+func (p *Paragraph) resolveSequence(seq []int, types []xbidi.Class, explicit []uint8) {
+  level := explicit[seq[0]]
+
+  // sos and eos from the levels on either side of the sequence
+  prevLevel := p.level
+  for i := seq[0] - 1; i >= 0; i-- {
+    if types[i] != xbidi.BN {
+      prevLevel = explicit[i]
+      break
+    }
+  }
+  nextLevel := p.level
+  if last := seq[len(seq)-1]; !isIsolateInitiator(p.classes[last]) {
+    for i := last + 1; i < len(types); i++ {
+      if types[i] != xbidi.BN {
+        nextLevel = explicit[i]
+        break
+      }
+    }
+  }
+  if level > prevLevel {
+    prevLevel = level
+  }
+  if level > nextLevel {
+    nextLevel = level
+  }
+  sos := directionOfLevel(prevLevel)
+  eos := directionOfLevel(nextLevel)
+
+  t := make([]xbidi.Class, len(seq))
+  for k, i := range seq {
+    t[k] = types[i]
+  }
+
+  // W1: non-spacing marks take the type of the previous character
+  for k := range t {
+    if t[k] != xbidi.NSM {
+      continue
+    }
+    if k == 0 {
+      t[k] = sos
+    } else if prev := t[k-1]; isIsolateInitiator(prev) || prev == xbidi.PDI {
+      t[k] = xbidi.ON
+    } else {
+      t[k] = prev
+    }
+  }
+
+  // W2: European numbers after Arabic letters become Arabic numbers
+  // W3: Arabic letters become R
+  lastStrong := sos
+  for k := range t {
+    switch t[k] {
+    case xbidi.L, xbidi.R, xbidi.AL:
+      lastStrong = t[k]
+    case xbidi.EN:
+      if lastStrong == xbidi.AL {
+        t[k] = xbidi.AN
+      }
+    }
+  }
+  for k := range t {
+    if t[k] == xbidi.AL {
+      t[k] = xbidi.R
+    }
+  }
+
+  // W4: single separators between numbers
+  for k := 1; k+1 < len(t); k++ {
+    switch {
+    case t[k] == xbidi.ES && t[k-1] == xbidi.EN && t[k+1] == xbidi.EN:
+      t[k] = xbidi.EN
+    case t[k] == xbidi.CS && t[k-1] == xbidi.EN && t[k+1] == xbidi.EN:
+      t[k] = xbidi.EN
+    case t[k] == xbidi.CS && t[k-1] == xbidi.AN && t[k+1] == xbidi.AN:
+      t[k] = xbidi.AN
+    }
+  }
+
+  // W5: terminators adjacent to European numbers
+  for k := 0; k < len(t); k++ {
+    if t[k] != xbidi.ET {
+      continue
+    }
+    end := k
+    for end < len(t) && t[end] == xbidi.ET {
+      end++
+    }
+    if (k > 0 && t[k-1] == xbidi.EN) || (end < len(t) && t[end] == xbidi.EN) {
+      for j := k; j < end; j++ {
+        t[j] = xbidi.EN
+      }
+    }
+    k = end - 1
+  }
+
+  // W6: remaining separators and terminators become neutral
+  for k := range t {
+    switch t[k] {
+    case xbidi.ES, xbidi.ET, xbidi.CS:
+      t[k] = xbidi.ON
+    }
+  }
+
+  // W7: European numbers after L become L
+  lastStrong = sos
+  for k := range t {
+    switch t[k] {
+    case xbidi.L, xbidi.R:
+      lastStrong = t[k]
+    case xbidi.EN:
+      if lastStrong == xbidi.L {
+        t[k] = xbidi.L
+      }
+    }
+  }
+
+  // N0: paired brackets
+  p.resolveBrackets(seq, t, sos, level)
+
+  // N1, N2: neutrals between strong types
+  for k := 0; k < len(t); k++ {
+    if !isNeutral(t[k]) {
+      continue
+    }
+    end := k
+    for end < len(t) && isNeutral(t[end]) {
+      end++
+    }
+    before, after := sos, eos
+    if k > 0 {
+      before = strongOf(t[k-1])
+    }
+    if end < len(t) {
+      after = strongOf(t[end])
+    }
+    dir := directionOfLevel(level)
+    if before == after && before != xbidi.ON {
+      dir = before
+    }
+    for j := k; j < end; j++ {
+      t[j] = dir
+    }
+    k = end - 1
+  }
+
+  // I1, I2: implicit levels
+  for k, i := range seq {
+    if level&1 == 0 {
+      switch t[k] {
+      case xbidi.R:
+        p.levels[i] = level + 1
+      case xbidi.AN, xbidi.EN:
+        p.levels[i] = level + 2
+      }
+    } else {
+      switch t[k] {
+      case xbidi.L, xbidi.EN, xbidi.AN:
+        p.levels[i] = level + 1
+      }
+    }
+  }
+}
+
+// bracketPair is a matched pair of positions within a run sequence.
+type bracketPair struct {
+  open, close int
+}
+
+// resolveBrackets applies rule N0 to the sequence types t.
+func (p *Paragraph) resolveBrackets(seq []int, t []xbidi.Class, sos xbidi.Class, level uint8) {
+  type opening struct {
+    closer rune
+    k      int
+  }
+  var stack []opening
+  var pairs []bracketPair
+Scan:
+  for k, i := range seq {
+    if t[k] != xbidi.ON {
+      continue
+    }
+    r := canonicalBracket(p.runes[i])
+    props, _ := xbidi.LookupRune(r)
+    if !props.IsBracket() {
+      continue
+    }
+    if props.IsOpeningBracket() {
+      if len(stack) == 63 {
+        break Scan
+      }
+      closer, _ := Mirror(r)
+      stack = append(stack, opening{closer: canonicalBracket(closer), k: k})
+      continue
+    }
+    for s := len(stack) - 1; s >= 0; s-- {
+      if stack[s].closer == r {
+        pairs = append(pairs, bracketPair{open: stack[s].k, close: k})
+        stack = stack[:s]
+        break
+      }
+    }
+  }
+  sort.Slice(pairs, func(a, b int) bool { return pairs[a].open < pairs[b].open })
+
+  embedding := directionOfLevel(level)
+  for _, pair := range pairs {
+    foundEmbedding, foundOpposite := false, false
+    for k := pair.open + 1; k < pair.close; k++ {
+      switch strongOf(t[k]) {
+      case embedding:
+        foundEmbedding = true
+      case xbidi.ON:
+      default:
+        foundOpposite = true
+      }
+    }
+
+    var dir xbidi.Class
+    switch {
+    case foundEmbedding:
+      dir = embedding
+    case foundOpposite:
+      // use the context before the opening bracket
+      context := sos
+      for k := pair.open - 1; k >= 0; k-- {
+        if s := strongOf(t[k]); s != xbidi.ON {
+          context = s
+          break
+        }
+      }
+      if context != embedding {
+        dir = context
+      } else {
+        dir = embedding
+      }
+    default:
+      continue
+    }
+
+    t[pair.open], t[pair.close] = dir, dir
+    // non-spacing marks following a bracket take its new type
+    for _, b := range []int{pair.open, pair.close} {
+      for k := b + 1; k < len(t) && p.classes[seq[k]] == xbidi.NSM; k++ {
+        t[k] = dir
+      }
+    }
+  }
+}
+
+// LineLevels returns the levels of the runes in the line [start, end) after
+// rule L1, which resets trailing whitespace and separators to the paragraph
+// level.
+func (p *Paragraph) LineLevels(start, end int) []uint8 {
+  levels := append([]uint8(nil), p.levels[start:end]...)
+  trailing := true
+  for i := end - 1; i >= start; i-- {
+    switch p.classes[i] {
+    case xbidi.S, xbidi.B:
+      levels[i-start] = p.level
+      trailing = true
+    case xbidi.WS, xbidi.LRI, xbidi.RLI, xbidi.FSI, xbidi.PDI,
+      xbidi.LRE, xbidi.RLE, xbidi.LRO, xbidi.RLO, xbidi.PDF, xbidi.BN:
+      if trailing {
+        levels[i-start] = p.level
+      }
+    default:
+      trailing = false
+    }
+  }
+  return levels
+}
+
+// Reorder returns the indices of the runes in the line [start, end) in
+// visual order, left to right (rule L2).
+func (p *Paragraph) Reorder(start, end int) []int {
+  return reorder(p.LineLevels(start, end), start)
+}
+
+// reorder reverses runs of levels from the highest level down to the lowest
+// odd level. Returned indices are offset by start.
+func reorder(levels []uint8, start int) []int {
+  order := make([]int, len(levels))
+  for k := range order {
+    order[k] = start + k
+  }
+  var highest, lowestOdd uint8 = 0, maxDepth + 2
+  for _, l := range levels {
+    if l > highest {
+      highest = l
+    }
+    if l&1 == 1 && l < lowestOdd {
+      lowestOdd = l
+    }
+  }
+  lv := append([]uint8(nil), levels...)
+  for l := highest; l >= lowestOdd && l > 0; l-- {
+    for k := 0; k < len(lv); k++ {
+      if lv[k] < l {
+        continue
+      }
+      end := k
+      for end < len(lv) && lv[end] >= l {
+        end++
+      }
+      for a, b := k, end-1; a < b; a, b = a+1, b-1 {
+        order[a], order[b] = order[b], order[a]
+        lv[a], lv[b] = lv[b], lv[a]
+      }
+      k = end
+    }
+  }
+  return order
+}
+
+// Runs returns the level runs of the line [start, end) in visual order,
+// left to right. Runes within a right-to-left run are displayed from End-1
+// down to Start.
+func (p *Paragraph) Runs(start, end int) []Run {
+  levels := p.LineLevels(start, end)
+  var runs []Run
+  for k := 0; k < len(levels); {
+    e := k
+    for e < len(levels) && levels[e] == levels[k] {
+      e++
+    }
+    runs = append(runs, Run{Start: start + k, End: start + e, Level: levels[k]})
+    k = e
+  }
+
+  // reorder the runs as units, following L2
+  runLevels := make([]uint8, len(runs))
+  for k, r := range runs {
+    runLevels[k] = r.Level
+  }
+  visual := make([]Run, len(runs))
+  for k, i := range reorder(runLevels, 0) {
+    visual[k] = runs[i]
+  }
+  return visual
+}
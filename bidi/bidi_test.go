@@ -0,0 +1,71 @@
+package bidi
+
+import (
+  "bufio"
+  "os"
+  "strconv"
+  "strings"
+  "testing"
+)
+
+// TestConformance runs a subset of BidiCharacterTest.txt: each line holds
+// hexadecimal runes, the paragraph direction, the resolved paragraph level,
+// the levels of runes ("x" for those removed by rule X9) and the visual
+// order of runes not removed, separated by semicolons.
+func TestConformance(t *testing.T) {
+  f, err := os.Open("testdata/BidiCharacterTest.txt")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer f.Close()
+  scanner := bufio.NewScanner(f)
+  tests := 0
+  for n := 1; scanner.Scan(); n++ {
+    line := scanner.Text()
+    if line == "" || line[0] == '#' {
+      continue
+    }
+    fields := strings.Split(line, ";")
+    if len(fields) != 5 {
+      t.Fatalf("line %d: %d fields", n, len(fields))
+    }
+    var runes []rune
+    for _, field := range strings.Fields(fields[0]) {
+      v, err := strconv.ParseUint(field, 16, 32)
+      if err != nil {
+        t.Fatalf("line %d: %v", n, err)
+      }
+      runes = append(runes, rune(v))
+    }
+    dir := map[string]Direction{"0": LeftToRight, "1": RightToLeft, "2": Auto}[fields[1]]
+    wantLevels := strings.Fields(fields[3])
+
+    p := NewParagraph(runes, dir)
+    if got := strconv.Itoa(int(p.Level())); got != fields[2] {
+      t.Errorf("line %d: paragraph level %s, want %s", n, got, fields[2])
+    }
+    levels := p.LineLevels(0, len(runes))
+    for i, want := range wantLevels {
+      if want != "x" && strconv.Itoa(int(levels[i])) != want {
+        t.Errorf("line %d: %s: levels %v, want %s", n, fields[0], levels, fields[3])
+        break
+      }
+    }
+    var order []string
+    for _, i := range p.Reorder(0, len(runes)) {
+      if wantLevels[i] != "x" {
+        order = append(order, strconv.Itoa(i))
+      }
+    }
+    if got, want := strings.Join(order, " "), strings.Join(strings.Fields(fields[4]), " "); got != want {
+      t.Errorf("line %d: %s: visual order %s, want %s", n, fields[0], got, want)
+    }
+    tests++
+  }
+  if err := scanner.Err(); err != nil {
+    t.Fatal(err)
+  }
+  if tests == 0 {
+    t.Fatal("no tests")
+  }
+}
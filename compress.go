@@ -0,0 +1,325 @@
+package ratlas
+
+import (
+  "encoding/binary"
+  "fmt"
+  "math"
+)
+
+// blockSize returns the size in bytes of the format's 4x4 texel blocks, or 0
+// if it isn't block compressed.
+func (format TextureFormat) blockSize() int {
+  switch format {
+  case TextureBC4, TextureEACR11:
+    return 8
+  case TextureBC5:
+    return 16
+  }
+  return 0
+}
+
+// forBlocks calls f with the 16 texels of channel c of each 4x4 block of p,
+// row by row, repeating edge texels to fill blocks past its edges.
+func (p *plane) forBlocks(c int, f func(block int, texels *[16]uint16)) {
+  bw := (p.w + 3) / 4
+  var texels [16]uint16
+  for by := 0; by < (p.h+3)/4; by++ {
+    for bx := 0; bx < bw; bx++ {
+      for i := range texels {
+        x, y := bx*4+i%4, by*4+i/4
+        if x >= p.w {
+          x = p.w - 1
+        }
+        if y >= p.h {
+          y = p.h - 1
+        }
+        texels[i] = p.pix[(y*p.w+x)*p.n+c]
+      }
+      f(by*bw+bx, &texels)
+    }
+  }
+}
+
+// setBlock sets channel c of the texels of a 4x4 block of p that are inside
+// it.
+func (p *plane) setBlock(c, block int, texels *[16]uint16) {
+  bw := (p.w + 3) / 4
+  for i, v := range texels {
+    x, y := block%bw*4+i%4, block/bw*4+i/4
+    if x < p.w && y < p.h {
+      p.pix[(y*p.w+x)*p.n+c] = v
+    }
+  }
+}
+
+// compress returns p block compressed in the format.
+func (format TextureFormat) compress(p *plane) []byte {
+  size := format.blockSize()
+  b := make([]byte, format.levelSize(p.w, p.h))
+  for c := 0; c < format.channels(); c++ {
+    p.forBlocks(c, func(block int, texels *[16]uint16) {
+      dst := b[block*size+8*c:]
+      if format == TextureEACR11 {
+        binary.BigEndian.PutUint64(dst, encodeEACR11(texels))
+      } else {
+        binary.LittleEndian.PutUint64(dst, encodeBC4(texels))
+      }
+    })
+  }
+  return b
+}
+
+// decompress returns the w by h image block compressed in b.
+func (format TextureFormat) decompress(b []byte, w, h int) *plane {
+  size := format.blockSize()
+  p := newPlane(w, h, format.channels())
+  for c := 0; c < p.n; c++ {
+    for block := 0; block < len(b)/size; block++ {
+      var texels [16]uint16
+      src := b[block*size+8*c:]
+      if format == TextureEACR11 {
+        decodeEACR11(binary.BigEndian.Uint64(src), &texels)
+      } else {
+        decodeBC4(binary.LittleEndian.Uint64(src), &texels)
+      }
+      p.setBlock(c, block, &texels)
+    }
+  }
+  return p
+}
+
+// bc4Palette returns the 8 values of a BC4 block's endpoints.
+func bc4Palette(r0, r1 uint8) [8]float32 {
+  a, b := float32(r0), float32(r1)
+  palette := [8]float32{a, b}
+  if r0 > r1 {
+    for i := 1; i < 7; i++ {
+      palette[i+1] = (a*float32(7-i) + b*float32(i)) / 7
+    }
+  } else {
+    for i := 1; i < 5; i++ {
+      palette[i+1] = (a*float32(5-i) + b*float32(i)) / 5
+    }
+    palette[6], palette[7] = 0, 255
+  }
+  return palette
+}
+
+// encodeBC4 returns a BC4 block of 16 texels, trying both of its modes and
+// endpoints slightly inside the texels' range, which often fit better.
+func encodeBC4(texels *[16]uint16) uint64 {
+  var v [16]float32
+  lo, hi := float32(255), float32(0)
+  // the six value mode has 0 and 255 for free, so its endpoints only
+  // need to span the other texels
+  lo6, hi6 := float32(255), float32(0)
+  for i, t := range texels {
+    v[i] = float32(t) / 0x101
+    if v[i] < lo {
+      lo = v[i]
+    }
+    if v[i] > hi {
+      hi = v[i]
+    }
+    if v[i] > 0.5 && v[i] < 254.5 {
+      if v[i] < lo6 {
+        lo6 = v[i]
+      }
+      if v[i] > hi6 {
+        hi6 = v[i]
+      }
+    }
+  }
+  if lo6 > hi6 {
+    lo6, hi6 = 0, 0
+  }
+
+  best, bestErr := uint64(0), float32(math.MaxFloat32)
+  try := func(r0, r1 uint8) {
+    palette := bc4Palette(r0, r1)
+    block := uint64(r0) | uint64(r1)<<8
+    var err float32
+    for i, t := range v {
+      index, d := 0, float32(math.MaxFloat32)
+      for j, p := range palette {
+        if e := (t - p) * (t - p); e < d {
+          index, d = j, e
+        }
+      }
+      err += d
+      block |= uint64(index) << uint(16+3*i)
+    }
+    if err < bestErr {
+      best, bestErr = block, err
+    }
+  }
+  for inset := 0; inset < 4; inset++ {
+    for outset := 0; outset < 4; outset++ {
+      r0, r1 := clampByte(hi-float32(inset)+0.5), clampByte(lo+float32(outset)+0.5)
+      if r0 > r1 {
+        try(r0, r1)
+      }
+      try(clampByte(lo6+float32(inset)+0.5), clampByte(hi6-float32(outset)+0.5))
+    }
+  }
+  return best
+}
+
+// decodeBC4 sets texels from a BC4 block.
+func decodeBC4(block uint64, texels *[16]uint16) {
+  palette := bc4Palette(uint8(block), uint8(block>>8))
+  for i := range texels {
+    texels[i] = uint16(palette[block>>uint(16+3*i)&7]*0x101 + 0.5)
+  }
+}
+
+func clampByte(v float32) uint8 {
+  if v <= 0 {
+    return 0
+  }
+  if v >= 255 {
+    return 255
+  }
+  return uint8(v)
+}
+
+// eacModifiers are the EAC modifier tables.
+var eacModifiers = [16][8]int{
+  {-3, -6, -9, -15, 2, 5, 8, 14},
+  {-3, -7, -10, -13, 2, 6, 9, 12},
+  {-2, -5, -8, -13, 1, 4, 7, 12},
+  {-2, -4, -6, -13, 1, 3, 5, 12},
+  {-3, -6, -8, -12, 2, 5, 7, 11},
+  {-3, -7, -9, -11, 2, 6, 8, 10},
+  {-4, -7, -8, -11, 3, 6, 7, 10},
+  {-3, -5, -8, -11, 2, 4, 7, 10},
+  {-2, -6, -8, -10, 1, 5, 7, 9},
+  {-2, -5, -8, -10, 1, 4, 7, 9},
+  {-2, -4, -8, -10, 1, 3, 7, 9},
+  {-2, -5, -7, -10, 1, 4, 6, 9},
+  {-3, -4, -7, -10, 2, 3, 6, 9},
+  {-1, -2, -3, -10, 0, 1, 2, 9},
+  {-4, -6, -8, -9, 3, 5, 7, 8},
+  {-3, -5, -7, -9, 2, 4, 6, 8},
+}
+
+// eacValue returns the 11 bit value of an EAC R11 block's modifier.
+func eacValue(base, multiplier, modifier int) int {
+  v := base*8 + 4 + modifier
+  if multiplier > 0 {
+    v = base*8 + 4 + modifier*multiplier*8
+  }
+  if v < 0 {
+    return 0
+  }
+  if v > 2047 {
+    return 2047
+  }
+  return v
+}
+
+// encodeEACR11 returns an EAC R11 block of 16 texels, searching the tables
+// with multipliers and base values near those spanning the texels' range.
+func encodeEACR11(texels *[16]uint16) uint64 {
+  var v [16]int
+  lo, hi := 2047, 0
+  for i, t := range texels {
+    v[i] = (int(t)*2047 + 0x7FFF) / 0xFFFF
+    if v[i] < lo {
+      lo = v[i]
+    }
+    if v[i] > hi {
+      hi = v[i]
+    }
+  }
+
+  best, bestErr := uint64(0), math.MaxInt32
+  for table, mods := range eacModifiers {
+    span := mods[7] - mods[3]
+    m := (hi - lo + 4*span) / (8 * span)
+    for multiplier := m - 1; multiplier <= m+1; multiplier++ {
+      if multiplier < 0 || multiplier > 15 {
+        continue
+      }
+      scale := multiplier * 8
+      if multiplier == 0 {
+        scale = 1
+      }
+      // the base centering the table's range on the texels'
+      center := (lo + hi - (mods[3]+mods[7])*scale) / 2
+      b := center / 8
+      for base := b - 1; base <= b+1; base++ {
+        if base < 0 || base > 255 {
+          continue
+        }
+        var values [8]int
+        for j, mod := range mods {
+          values[j] = eacValue(base, multiplier, mod)
+        }
+        block := uint64(base)<<56 | uint64(multiplier)<<52 | uint64(table)<<48
+        err := 0
+        for i, t := range v {
+          index, d := 0, math.MaxInt32
+          for j, value := range values {
+            if e := (t - value) * (t - value); e < d {
+              index, d = j, e
+            }
+          }
+          err += d
+          // indices are stored column by column
+          block |= uint64(index) << uint(45-3*(i%4*4+i/4))
+        }
+        if err < bestErr {
+          best, bestErr = block, err
+        }
+      }
+    }
+  }
+  return best
+}
+
+// decodeEACR11 sets texels from an EAC R11 block.
+func decodeEACR11(block uint64, texels *[16]uint16) {
+  base, multiplier, mods := int(block>>56), int(block>>52&15), eacModifiers[block>>48&15]
+  for i := range texels {
+    index := block >> uint(45-3*(i%4*4+i/4)) & 7
+    v := eacValue(base, multiplier, mods[index])
+    texels[i] = uint16(v<<5 | v>>6)
+  }
+}
+
+// PageError is how far a block compressed page is from its texels, from 0 to
+// 1 over its channels.
+type PageError struct {
+  Max float32
+  RMS float32
+
+  // PSNR is the peak signal to noise ratio in decibels, infinite if the
+  // page is unchanged.
+  PSNR float64
+}
+
+// CompressionErrors compresses each page in a block compressed format and
+// reports its error against the uncompressed texels of Atlas.Images.
+func (atlas *Atlas) CompressionErrors(format TextureFormat) ([]PageError, error) {
+  if format.blockSize() == 0 {
+    return nil, fmt.Errorf("ratlas: texture format %d isn't block compressed", format)
+  }
+  var errs []PageError
+  for _, img := range atlas.Images {
+    p := pagePlane(img, format.channels())
+    q := format.decode(format.encode(p), p.w, p.h)
+    var e PageError
+    var sum float64
+    for i, v := range p.pix {
+      d := math.Abs(float64(v)-float64(q.pix[i])) / 0xFFFF
+      e.Max = float32(math.Max(float64(e.Max), d))
+      sum += d * d
+    }
+    mse := sum / float64(len(p.pix))
+    e.RMS = float32(math.Sqrt(mse))
+    e.PSNR = 10 * math.Log10(1/mse)
+    errs = append(errs, e)
+  }
+  return errs, nil
+}
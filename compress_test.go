@@ -0,0 +1,49 @@
+package ratlas
+
+import (
+  "math"
+  "testing"
+)
+
+// TestCompressionErrors checks that the reported errors of block compressed
+// pages are the errors of decoding them, and small enough for coverage.
+func TestCompressionErrors(t *testing.T) {
+  for _, channels := range []bool{false, true} {
+    atlas := textureTestAtlas(t, channels)
+    formats := []TextureFormat{TextureBC4, TextureEACR11}
+    if channels {
+      formats = []TextureFormat{TextureBC4, TextureBC5, TextureEACR11}
+    }
+    for _, format := range formats {
+      errs, err := atlas.CompressionErrors(format)
+      if err != nil {
+        t.Fatal(err)
+      }
+      if len(errs) != len(atlas.Images) {
+        t.Fatalf("format %d: %d page errors for %d pages", format, len(errs), len(atlas.Images))
+      }
+      for i, img := range atlas.Images {
+        p := pagePlane(img, format.channels())
+        q := format.decode(format.encode(p), p.w, p.h)
+        var worst float32
+        for k, v := range p.pix {
+          d := float32(math.Abs(float64(v)-float64(q.pix[k]))) / 0xFFFF
+          worst = float32(math.Max(float64(worst), float64(d)))
+        }
+        e := errs[i]
+        if worst != e.Max || e.RMS > e.Max || e.Max == 0 {
+          t.Errorf("format %d: page %d decodes off by up to %v, reported max %v, RMS %v", format, i, worst, e.Max, e.RMS)
+        }
+        // blocks from black to white have 8 levels, 1/14 apart at best, so
+        // leave the encoder some room
+        if e.Max > 0.15 || e.PSNR < 35 {
+          t.Errorf("format %d: page %d has max error %v, PSNR %.1f dB", format, i, e.Max, e.PSNR)
+        }
+      }
+    }
+  }
+  var atlas Atlas
+  if _, err := atlas.CompressionErrors(TextureR8); err == nil {
+    t.Error("no error for an uncompressed format")
+  }
+}
@@ -0,0 +1,97 @@
+package ratlas
+
+import (
+  "image"
+  "image/color"
+  "math"
+)
+
+// Coverage adjusts the coverage values of baked glyphs, so text keeps its
+// weight when blended linearly in a gamma-encoded framebuffer.
+type Coverage struct {
+  // Gamma raises coverage to the power of 1/Gamma: above 1 for light text
+  // on dark backgrounds, which otherwise looks thin, below 1 for dark text
+  // on light ones, which otherwise looks heavy. Zero means 1.
+  Gamma float32
+  
+  // Contrast steepens coverage around one half, from 0 for none to 1 for
+  // twice as steep, sharpening edges.
+  Contrast float32
+  
+  // StemDarkening grows stems by this many pixels at 10 pixels per em and
+  // below, fading out to none at 40 pixels per em, to keep small text from
+  // looking faint; a few tenths of a pixel is plenty. Advances are unchanged.
+  StemDarkening float32
+}
+
+// identity reports whether the gamma and contrast adjustment does nothing.
+func (c Coverage) identity() bool {
+  return (c.Gamma == 0 || c.Gamma == 1) && c.Contrast == 0
+}
+
+// curve applies the gamma and contrast adjustment to a coverage value from 0 to 1.
+func (c Coverage) curve(v float64) float64 {
+  gamma := float64(c.Gamma)
+  if gamma == 0 {
+    gamma = 1
+  }
+  v = (v-0.5)*(1+float64(c.Contrast)) + 0.5
+  return math.Pow(math.Max(0, math.Min(1, v)), 1/gamma)
+}
+
+// table returns the gamma and contrast adjustment as a lookup table, or nil
+// if there's none.
+func (c Coverage) table() *[256]uint8 {
+  if c.identity() {
+    return nil
+  }
+  var table [256]uint8
+  for i := range table {
+    table[i] = uint8(c.curve(float64(i)/255)*255 + 0.5)
+  }
+  return &table
+}
+
+// adjust applies the gamma and contrast adjustment to coverage values.
+func (c Coverage) adjust(pix []uint8) {
+  table := c.table()
+  if table == nil {
+    return
+  }
+  for i, v := range pix {
+    pix[i] = table[v]
+  }
+}
+
+// adjust16 applies the gamma and contrast adjustment to the coverage of a
+// 16 bit image.
+func (c Coverage) adjust16(img *image.Gray16) {
+  if c.identity() {
+    return
+  }
+  b := img.Bounds()
+  for y := b.Min.Y; y < b.Max.Y; y++ {
+    for x := b.Min.X; x < b.Max.X; x++ {
+      v := float64(img.Gray16At(x, y).Y) / 0xFFFF
+      img.SetGray16(x, y, color.Gray16{uint16(c.curve(v)*0xFFFF + 0.5)})
+    }
+  }
+}
+
+// darkening returns how many pixels stem darkening grows stems by at the atlas size.
+func (atlas *Atlas) darkening() float32 {
+  ppem := atlas.PixelsPerEm()
+  fade := (40 - ppem) / (40 - 10)
+  if fade <= 0 {
+    return 0
+  }
+  if fade > 1 {
+    fade = 1
+  }
+  return atlas.Coverage.StemDarkening * fade
+}
+
+// dilation returns how far glyph edges move out when drawn, by Embolden and stem darkening.
+func (atlas *Atlas) dilation() float32 {
+  return (atlas.Embolden + atlas.darkening()) / 2
+}
@@ -0,0 +1,124 @@
+package ratlas
+
+import (
+  "math"
+  "testing"
+)
+
+// inkSum returns the total coverage of the items of runes.
+func inkSum(t *testing.T, atlas *Atlas, runes string) float64 {
+  var sum float64
+  for _, r := range runes {
+    for _, v := range layerValues(t, atlas, atlas.Items[r], EffectFill) {
+      sum += float64(v)
+    }
+  }
+  return sum
+}
+
+// TestCoverageCurve checks that the gamma and contrast curve keeps 0 and 1,
+// never decreases, and moves coverage the way its parameters should.
+func TestCoverageCurve(t *testing.T) {
+  if (Coverage{}).table() != nil || (Coverage{Gamma: 1}).table() != nil {
+    t.Errorf("identity coverage has a table")
+  }
+  gammas := []float32{0.5, 1, 1.8, 2.2}
+  for _, contrast := range []float32{0, 0.5, 1} {
+    for i, gamma := range gammas {
+      c := Coverage{Gamma: gamma, Contrast: contrast}
+      if c.curve(0) != 0 || c.curve(1) != 1 {
+        t.Errorf("%+v: 0 and 1 become %v and %v", c, c.curve(0), c.curve(1))
+      }
+      for v := 0.0; v < 1; v += 1.0 / 64 {
+        if c.curve(v+1.0/64) < c.curve(v) {
+          t.Errorf("%+v: curve decreases at %v", c, v)
+        }
+        if i > 0 {
+          // more gamma, more coverage
+          if lower := (Coverage{Gamma: gammas[i-1], Contrast: contrast}); c.curve(v) < lower.curve(v) {
+            t.Errorf("%+v: %v becomes %v, less than %v with gamma %v", c, v, c.curve(v), lower.curve(v), lower.Gamma)
+          }
+        }
+      }
+      if table := c.table(); table != nil {
+        for k := 1; k < len(table); k++ {
+          if table[k] < table[k-1] {
+            t.Errorf("%+v: table decreases at %d", c, k)
+          }
+        }
+      }
+    }
+  }
+  // contrast steepens around one half
+  for _, contrast := range []float32{0.25, 0.5, 1} {
+    c := Coverage{Contrast: contrast}
+    if c.curve(0.25) >= 0.25 || c.curve(0.75) <= 0.75 || math.Abs(c.curve(0.5)-0.5) > 1e-9 {
+      t.Errorf("%+v: 0.25, 0.5 and 0.75 become %v, %v and %v", c, c.curve(0.25), c.curve(0.5), c.curve(0.75))
+    }
+  }
+}
+
+// TestCoverageBaked bakes atlases with increasing gamma, and with increasing
+// stem darkening, and checks that coverage grows with each, and that stem
+// darkening grows Pad but leaves advances alone.
+func TestCoverageBaked(t *testing.T) {
+  data := testFont(t)
+  const runes = "Hamburgefonstiv"
+  opts := Options{FontPt: 12, Width: 256, Height: 256, Pad: 1}
+  for _, format := range []PixelFormat{FormatGray, FormatGray16} {
+    opts.Format = format
+    last := -1.0
+    for _, gamma := range []float32{0.5, 1, 1.8, 2.2} {
+      opts.Coverage = Coverage{Gamma: gamma}
+      atlas := NewWithOptions(&data, []rune(runes), opts)
+      if sum := inkSum(t, &atlas, runes); sum <= last {
+        t.Errorf("format %d: gamma %v gives coverage %.1f, no more than %.1f", format, gamma, sum, last)
+      } else {
+        last = sum
+      }
+    }
+  }
+
+  opts.Format = FormatGray
+  plain := NewWithOptions(&data, []rune(runes), Options{FontPt: 12, Width: 256, Height: 256, Pad: 1})
+  last := inkSum(t, &plain, runes)
+  for _, darkening := range []float32{0.25, 0.5, 1, 2} {
+    opts.Coverage = Coverage{StemDarkening: darkening}
+    atlas := NewWithOptions(&data, []rune(runes), opts)
+    // 12 pixels per em darkens by 28/30 of StemDarkening
+    want := darkening * 28 / 30
+    if d := atlas.darkening(); math.Abs(float64(d-want)) > 1e-5 {
+      t.Errorf("darkening %v: stems grow by %v, want %v", darkening, d, want)
+    }
+    if pad := opts.Pad + int(math.Ceil(float64(want/2))); atlas.Pad != pad {
+      t.Errorf("darkening %v: Pad %d, want %d", darkening, atlas.Pad, pad)
+    }
+    if sum := inkSum(t, &atlas, runes); sum <= last {
+      t.Errorf("darkening %v: coverage %.1f, no more than %.1f", darkening, sum, last)
+    } else {
+      last = sum
+    }
+    for _, r := range runes {
+      if atlas.Items[r].Advance != plain.Items[r].Advance {
+        t.Errorf("darkening %v: %q advances %v, not %v", darkening, r, atlas.Items[r].Advance, plain.Items[r].Advance)
+      }
+    }
+  }
+
+  // darkening fades out from 10 to 40 pixels per em
+  for _, test := range []struct {
+    fontPt    float64
+    darkening float32
+  }{
+    {8, 1}, {10, 1}, {25, 0.5}, {40, 0}, {60, 0},
+  } {
+    atlas := Atlas{FontPt: test.fontPt, Coverage: Coverage{StemDarkening: 1}}
+    if d := atlas.darkening(); math.Abs(float64(d-test.darkening)) > 1e-5 {
+      t.Errorf("%v pixels per em: darkening %v, want %v", test.fontPt, d, test.darkening)
+    }
+  }
+  atlas := NewWithOptions(&data, []rune(runes), Options{FontPt: 48, Width: 256, Height: 256, Pad: 1, Coverage: Coverage{StemDarkening: 1}})
+  if atlas.Pad != 1 {
+    t.Errorf("Pad %d at 48 pixels per em, want 1", atlas.Pad)
+  }
+}
@@ -0,0 +1,139 @@
+package ratlas
+
+import (
+  "bytes"
+  "encoding/binary"
+  "fmt"
+)
+
+// dxgiFormat returns the format's DXGI_FORMAT, as DDS files record it.
+func (format TextureFormat) dxgiFormat() uint32 {
+  switch format {
+  case TextureRG8:
+    return 49 // DXGI_FORMAT_R8G8_UNORM
+  case TextureRGBA8:
+    return 28 // DXGI_FORMAT_R8G8B8A8_UNORM
+  case TextureR16:
+    return 56 // DXGI_FORMAT_R16_UNORM
+  case TextureBC4:
+    return 80 // DXGI_FORMAT_BC4_UNORM
+  case TextureBC5:
+    return 83 // DXGI_FORMAT_BC5_UNORM
+  case TextureEACR11:
+    return 0 // DXGI_FORMAT_UNKNOWN
+  }
+  return 61 // DXGI_FORMAT_R8_UNORM
+}
+
+// DDS header flags
+const (
+  ddsCaps = 0x1
+  ddsHeight = 0x2
+  ddsWidth = 0x4
+  ddsPitch = 0x8
+  ddsPixelFormat = 0x1000
+  ddsMipMapCount = 0x20000
+  ddsLinearSize = 0x80000
+  ddsFourCC = 0x4
+  ddsComplex = 0x8
+  ddsTexture = 0x1000
+  ddsMipMap = 0x400000
+  ddsTexture2D = 3
+)
+
+// SaveDDSFiles saves pages to DDS files with a DX10 header, name-0.dds and so
+// on, or name.dds for an array texture.
+func (atlas *Atlas) SaveDDSFiles(name string, opts TextureOptions) error {
+  if opts.Format.dxgiFormat() == 0 {
+    return fmt.Errorf("ratlas: DDS files can't hold texture format %d", opts.Format)
+  }
+  return atlas.saveTextureFiles(name, ".dds", opts, func(layers [][][]byte, w, h int, array bool) []byte {
+    return encodeDDS(opts.Format, layers, w, h)
+  })
+}
+
+// LoadDDSFiles loads pages from the top mip levels of DDS files, in the
+// formats SaveDDSFiles writes, one per array slice. Coverage pages are
+// converted to the atlas' Format, so load its gob first.
+func (atlas *Atlas) LoadDDSFiles(fileNames []string) error {
+  return atlas.loadTextureFiles(fileNames, decodeDDS)
+}
+
+// encodeDDS returns a DDS file of layers, each a mip chain.
+func encodeDDS(format TextureFormat, layers [][][]byte, w, h int) []byte {
+  levels := len(layers[0])
+  flags := uint32(ddsCaps | ddsHeight | ddsWidth | ddsPixelFormat | ddsMipMapCount)
+  pitch := w * format.texelSize()
+  if format.blockSize() > 0 {
+    flags |= ddsLinearSize
+    pitch = format.levelSize(w, h)
+  } else {
+    flags |= ddsPitch
+  }
+  caps := uint32(ddsTexture)
+  if levels > 1 {
+    caps |= ddsComplex | ddsMipMap
+  }
+
+  b := new(bytes.Buffer)
+  b.WriteString("DDS ")
+  put(b, uint32(124), flags, uint32(h), uint32(w), uint32(pitch), uint32(0), uint32(levels), [11]uint32{})
+  put(b, uint32(32), uint32(ddsFourCC), []byte("DX10"), [5]uint32{})
+  put(b, caps, [4]uint32{})
+  put(b, format.dxgiFormat(), uint32(ddsTexture2D), uint32(0), uint32(len(layers)), uint32(0))
+  for _, layer := range layers {
+    for _, level := range layer {
+      b.Write(level)
+    }
+  }
+  return b.Bytes()
+}
+
+// decodeDDS returns the top level of each array slice of a DDS file.
+func decodeDDS(b []byte) (*textureFile, error) {
+  if len(b) < 148 || string(b[:4]) != "DDS " {
+    return nil, fmt.Errorf("not a DDS file")
+  }
+  le := binary.LittleEndian
+  h, w, levels := int(le.Uint32(b[12:])), int(le.Uint32(b[16:])), int(le.Uint32(b[28:]))
+  if string(b[84:88]) != "DX10" || le.Uint32(b[132:]) != ddsTexture2D {
+    return nil, fmt.Errorf("only 2D textures with a DX10 header are supported")
+  }
+  dxgiFormat, layers := le.Uint32(b[128:]), int(le.Uint32(b[140:]))
+  file := &textureFile{w: w, h: h}
+  found := false
+  for _, format := range textureFormats {
+    if format.dxgiFormat() == dxgiFormat && dxgiFormat != 0 {
+      file.format, found = format, true
+    }
+  }
+  if !found {
+    return nil, fmt.Errorf("unsupported DXGI_FORMAT %d", dxgiFormat)
+  }
+  if levels == 0 {
+    levels = 1
+  }
+
+  // each slice holds its whole mip chain
+  chain := 0
+  for l, lw, lh := 0, w, h; l < levels; l++ {
+    chain += file.format.levelSize(lw, lh)
+    lw, lh = max1(lw/2), max1(lh/2)
+  }
+  if 148+chain*layers > len(b) {
+    return nil, fmt.Errorf("%d bytes of texels, not %d", len(b)-148, chain*layers)
+  }
+  size := file.format.levelSize(w, h)
+  for i := 0; i < layers; i++ {
+    start := 148 + i*chain
+    file.layers = append(file.layers, b[start:start+size])
+  }
+  return file, nil
+}
+
+func max1(v int) int {
+  if v < 1 {
+    return 1
+  }
+  return v
+}
@@ -0,0 +1,398 @@
+package ratlas
+
+import (
+  "image"
+  "image/draw"
+  "math"
+
+  "github.com/golang/freetype/truetype"
+  "golang.org/x/image/vector"
+)
+
+// Join is how the corners of a stroked outline are joined.
+type Join int
+
+const (
+  JoinRound Join = iota
+  JoinMiter
+  JoinBevel
+)
+
+// miterLimit is the longest a miter join gets, in outline widths, before it's beveled instead.
+const miterLimit = 4
+
+// EffectStorage is where an atlas stores baked effects.
+type EffectStorage int
+
+const (
+  // EffectChannels stores effects in the channels of *image.NRGBA pages: the
+  // glyph in red, so shaders reading red still work, outline in green, shadow
+  // in blue and glow in alpha.
+  EffectChannels EffectStorage = iota
+
+  // EffectPages keeps glyphs on Gray pages and stores each effect on Gray
+  // pages of its own, following the glyph pages in Atlas.Images in the order
+  // outline, shadow, glow.
+  EffectPages
+)
+
+// Effects configures glyph effects baked into an atlas, each at the same
+// place as its glyph on its own channel or page, so one quad draws any of
+// them. The padding of items grows to fit the effects, and BearingX and
+// Descent with it, so layout is unchanged.
+type Effects struct {
+  // OutlineWidth is the width in pixels of an outline stroked outside glyph
+  // edges, or zero for none. The outline layer holds the glyph along with its
+  // outline, to be drawn under the glyph.
+  OutlineWidth float32
+  OutlineJoin Join
+
+  // Shadow bakes the glyph offset by (ShadowX, ShadowY) pixels and blurred by
+  // a Gaussian with a standard deviation of ShadowBlur pixels.
+  Shadow bool
+  ShadowX, ShadowY, ShadowBlur float32
+
+  // GlowRadius is how far in pixels a glow fades out around the glyph, or zero for none.
+  GlowRadius float32
+
+  Storage EffectStorage
+}
+
+// Effect is a layer of an atlas: the glyphs or one of their baked effects.
+// As a channel index it's the channel the layer is stored in by EffectChannels.
+type Effect int
+
+const (
+  EffectFill Effect = iota
+  EffectOutline
+  EffectShadow
+  EffectGlow
+)
+
+// layers returns the enabled effects in storage order.
+func (e Effects) layers() []Effect {
+  var layers []Effect
+  if e.OutlineWidth > 0 {
+    layers = append(layers, EffectOutline)
+  }
+  if e.Shadow {
+    layers = append(layers, EffectShadow)
+  }
+  if e.GlowRadius > 0 {
+    layers = append(layers, EffectGlow)
+  }
+  return layers
+}
+
+// pad returns the padding the effects need around glyphs, on top of Options.Pad.
+func (e Effects) pad() int {
+  extent := 0.0
+  if e.OutlineWidth > 0 {
+    extent = math.Max(extent, float64(e.OutlineWidth))
+  }
+  if e.Shadow {
+    offset := math.Max(math.Abs(float64(e.ShadowX)), math.Abs(float64(e.ShadowY)))
+    extent = math.Max(extent, offset+3*math.Abs(float64(e.ShadowBlur)))
+  }
+  if e.GlowRadius > 0 {
+    extent = math.Max(extent, float64(e.GlowRadius))
+  }
+  if extent == 0 {
+    return 0
+  }
+  return int(math.Ceil(extent)) + 1
+}
+
+// EffectImage returns the image holding an effect layer of an item and the
+// channel of it to sample, from 0 for red to 3 for alpha, or false if the
+// atlas doesn't have the effect.
+func (atlas *Atlas) EffectImage(item *AtlasItem, effect Effect) (draw.Image, int, bool) {
+  if item.ImageIndex >= len(atlas.Images) {
+    return nil, 0, false
+  }
+  if effect == EffectFill {
+    return atlas.Images[item.ImageIndex], 0, true
+  }
+  layers := atlas.Effects.layers()
+  for k, layer := range layers {
+    if layer != effect {
+      continue
+    }
+    if atlas.Effects.Storage == EffectChannels {
+      return atlas.Images[item.ImageIndex], int(effect), true
+    }
+    pages := len(atlas.Images) / (len(layers) + 1)
+    return atlas.Images[item.ImageIndex+(k+1)*pages], 0, true
+  }
+  return nil, 0, false
+}
+
+// bakeEffects renders the effect layers of a glyph from its coverage in fill,
+// drawn as by drawGlyph, indexed by Effect. Layers of disabled effects are nil.
+func (atlas *Atlas) bakeEffects(fill *image.Gray, index truetype.Index, pad int, phase float32) ([4]*image.Gray, error) {
+  var layers [4]*image.Gray
+  layers[EffectFill] = fill
+  e := atlas.Effects
+  w, h := fill.Bounds().Dx(), fill.Bounds().Dy()
+  coverage := make([]float32, w*h)
+  for i, v := range fill.Pix {
+    coverage[i] = float32(v) / 255
+  }
+
+  if e.Shadow {
+    shadow := shift(coverage, w, h, e.ShadowX, e.ShadowY)
+    layers[EffectShadow] = grayImage(blur(shadow, w, h, e.ShadowBlur), w, h, 1)
+  }
+  if e.GlowRadius > 0 {
+    // fading out by about three standard deviations, doubled to stay opaque near the glyph
+    layers[EffectGlow] = grayImage(blur(coverage, w, h, e.GlowRadius/3), w, h, 2)
+  }
+  if e.OutlineWidth > 0 {
+    layers[EffectOutline] = fill
+    glyphBuf, err := atlas.loadGlyph(index)
+    if err != nil {
+      return layers, err
+    }
+    dx, dy := glyphOrigin(glyphBuf, pad, phase)
+    outline := image.NewGray(fill.Bounds())
+    atlas.strokeGlyph(glyphBuf, outline, dx, dy, 1, 1, e.OutlineWidth+atlas.dilation(), e.OutlineJoin)
+    for i, v := range fill.Pix {
+      if v > outline.Pix[i] {
+        outline.Pix[i] = v
+      }
+    }
+    layers[EffectOutline] = outline
+  }
+  return layers, nil
+}
+
+// packChannels copies the layers of a glyph, indexed by Effect, into the channels of rect on page.
+func packChannels(page *image.NRGBA, rect image.Rectangle, layers [4]*image.Gray) {
+  for y := 0; y < rect.Dy(); y++ {
+    for x := 0; x < rect.Dx(); x++ {
+      i := page.PixOffset(rect.Min.X+x, rect.Min.Y+y)
+      for c, layer := range layers {
+        if layer != nil {
+          page.Pix[i+c] = layer.Pix[layer.PixOffset(x, y)]
+        }
+      }
+    }
+  }
+}
+
+// grayImage converts w by h coverage values, multiplied by gain, to an image.
+func grayImage(v []float32, w, h int, gain float32) *image.Gray {
+  img := image.NewGray(image.Rect(0, 0, w, h))
+  for i, c := range v {
+    c *= gain
+    if c > 1 {
+      c = 1
+    }
+    img.Pix[i] = uint8(c*255 + 0.5)
+  }
+  return img
+}
+
+// shift returns w by h values moved by (dx, dy), filtered bilinearly.
+func shift(v []float32, w, h int, dx, dy float32) []float32 {
+  at := func(x, y int) float32 {
+    if x < 0 || y < 0 || x >= w || y >= h {
+      return 0
+    }
+    return v[y*w+x]
+  }
+  ix, iy := int(math.Floor(float64(dx))), int(math.Floor(float64(dy)))
+  fx, fy := dx-float32(ix), dy-float32(iy)
+  out := make([]float32, len(v))
+  for y := 0; y < h; y++ {
+    for x := 0; x < w; x++ {
+      sx, sy := x-ix, y-iy
+      out[y*w+x] = at(sx, sy)*(1-fx)*(1-fy) + at(sx-1, sy)*fx*(1-fy) +
+        at(sx, sy-1)*(1-fx)*fy + at(sx-1, sy-1)*fx*fy
+    }
+  }
+  return out
+}
+
+// blur returns w by h values blurred by a Gaussian with a standard deviation of sigma.
+func blur(v []float32, w, h int, sigma float32) []float32 {
+  if sigma <= 0 {
+    return v
+  }
+  radius := int(math.Ceil(float64(3 * sigma)))
+  kernel := make([]float32, 2*radius+1)
+  var sum float32
+  for i := range kernel {
+    x := float64(i - radius)
+    kernel[i] = float32(math.Exp(-x * x / (2 * float64(sigma) * float64(sigma))))
+    sum += kernel[i]
+  }
+  for i := range kernel {
+    kernel[i] /= sum
+  }
+
+  // separably, across then down
+  tmp := make([]float32, len(v))
+  out := make([]float32, len(v))
+  for y := 0; y < h; y++ {
+    for x := 0; x < w; x++ {
+      var s float32
+      for k, weight := range kernel {
+        if xx := x + k - radius; xx >= 0 && xx < w {
+          s += v[y*w+xx] * weight
+        }
+      }
+      tmp[y*w+x] = s
+    }
+  }
+  for y := 0; y < h; y++ {
+    for x := 0; x < w; x++ {
+      var s float32
+      for k, weight := range kernel {
+        if yy := y + k - radius; yy >= 0 && yy < h {
+          s += tmp[yy*w+x] * weight
+        }
+      }
+      out[y*w+x] = s
+    }
+  }
+  return out
+}
+
+type fpoint struct {
+  x, y float32
+}
+
+func (p fpoint) add(q fpoint) fpoint {
+  return fpoint{p.x + q.x, p.y + q.y}
+}
+
+func (p fpoint) mul(s float32) fpoint {
+  return fpoint{p.x * s, p.y * s}
+}
+
+func (p fpoint) len() float32 {
+  return float32(math.Hypot(float64(p.x), float64(p.y)))
+}
+
+// flattener collects contours as polygons, approximating curves with lines.
+type flattener struct {
+  contours [][]fpoint
+  current []fpoint
+}
+
+func (f *flattener) MoveTo(x, y float32) {
+  f.current = []fpoint{{x, y}}
+}
+
+func (f *flattener) LineTo(x, y float32) {
+  f.current = append(f.current, fpoint{x, y})
+}
+
+func (f *flattener) QuadTo(bx, by, cx, cy float32) {
+  const steps = 8
+  a := f.current[len(f.current)-1]
+  for i := 1; i <= steps; i++ {
+    t := float32(i) / steps
+    u := 1 - t
+    f.current = append(f.current, fpoint{u*u*a.x + 2*u*t*bx + t*t*cx, u*u*a.y + 2*u*t*by + t*t*cy})
+  }
+}
+
+func (f *flattener) ClosePath() {
+  f.contours = append(f.contours, f.current)
+  f.current = nil
+}
+
+// strokeGlyph draws the edges of a glyph offset by (dx, dy) in white onto
+// dst, reaching w either side of them, then scaled by (sx, sy).
+func (atlas *Atlas) strokeGlyph(glyphBuf *truetype.GlyphBuf, dst draw.Image, dx, dy, sx, sy, w float32, join Join) {
+  var f flattener
+  e0 := 0
+  for _, e1 := range glyphBuf.Ends {
+    drawContour(&f, glyphBuf.Points[e0:e1], dx, dy)
+    e0 = e1
+  }
+  size := dst.Bounds().Size()
+  r := vector.NewRasterizer(size.X, size.Y)
+  for _, contour := range f.contours {
+    stroke(scaledPather{r, sx, sy}, contour, w, join)
+  }
+  r.Draw(dst, dst.Bounds(), image.White, image.Point{})
+}
+
+// stroke adds the outline of a closed polygon to r, reaching w either side of its edges.
+func stroke(r pather, ps []fpoint, w float32, join Join) {
+  var pts []fpoint
+  for _, p := range ps {
+    if len(pts) == 0 || p != pts[len(pts)-1] {
+      pts = append(pts, p)
+    }
+  }
+  for len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+    pts = pts[:len(pts)-1]
+  }
+  n := len(pts)
+  if n < 2 {
+    return
+  }
+  normal := func(a, b fpoint) fpoint {
+    d := fpoint{b.x - a.x, b.y - a.y}
+    return fpoint{-d.y, d.x}.mul(w / d.len())
+  }
+
+  for i, p := range pts {
+    prev, next := pts[(i+n-1)%n], pts[(i+1)%n]
+    n1, n2 := normal(prev, p), normal(p, next)
+    addPolygon(r, p.add(n2), next.add(n2), next.add(n2.mul(-1)), p.add(n2.mul(-1)))
+
+    // join the edges ending and starting at p, on both sides
+    if join == JoinRound {
+      const sides = 16
+      circle := make([]fpoint, sides)
+      for k := range circle {
+        angle := 2 * math.Pi * float64(k) / sides
+        circle[k] = p.add(fpoint{float32(math.Cos(angle)), float32(math.Sin(angle))}.mul(w))
+      }
+      addPolygon(r, circle...)
+      continue
+    }
+    for _, s := range []float32{1, -1} {
+      a, b := n1.mul(s), n2.mul(s)
+      m := a.add(b)
+      if ml := m.len(); join == JoinMiter && ml > 0 {
+        // the miter's tip is w/cos(θ/2) from p, where cos(θ/2) = |m|/2w
+        if d := 2 * w * w / ml; d <= miterLimit*w {
+          addPolygon(r, p, p.add(a), p.add(m.mul(d/ml)), p.add(b))
+          continue
+        }
+      }
+      addPolygon(r, p, p.add(a), p.add(b))
+    }
+  }
+}
+
+// addPolygon adds a polygon to r, always wound the same way so that
+// overlapping polygons add up instead of cancelling out.
+func addPolygon(r pather, ps ...fpoint) {
+  var area float32
+  for i, p := range ps {
+    q := ps[(i+1)%len(ps)]
+    area += p.x*q.y - q.x*p.y
+  }
+  if area == 0 {
+    return
+  }
+  if area < 0 {
+    reversed := make([]fpoint, len(ps))
+    for i, p := range ps {
+      reversed[len(ps)-1-i] = p
+    }
+    ps = reversed
+  }
+  r.MoveTo(ps[0].x, ps[0].y)
+  for _, p := range ps[1:] {
+    r.LineTo(p.x, p.y)
+  }
+  r.ClosePath()
+}
@@ -0,0 +1,131 @@
+package ratlas
+
+import (
+  "math"
+  "testing"
+)
+
+// layerValues returns the texels of an item's effect layer, row by row, from
+// 0 to 1.
+func layerValues(t *testing.T, atlas *Atlas, item *AtlasItem, effect Effect) []float32 {
+  img, channel, ok := atlas.EffectImage(item, effect)
+  if !ok {
+    t.Fatalf("no layer %d", effect)
+  }
+  var values []float32
+  r := itemRect(item)
+  for y := r.Min.Y; y < r.Max.Y; y++ {
+    for x := r.Min.X; x < r.Max.X; x++ {
+      values = append(values, float32(texels(img, x, y)[channel])/0xFFFF)
+    }
+  }
+  return values
+}
+
+func TestEffectPad(t *testing.T) {
+  for _, test := range []struct {
+    effects Effects
+    pad     int
+  }{
+    {Effects{}, 0},
+    {Effects{Shadow: false, ShadowX: 5}, 0},
+    {Effects{OutlineWidth: 2}, 3},
+    {Effects{OutlineWidth: 1.5}, 3},
+    {Effects{Shadow: true, ShadowX: 2, ShadowY: -3, ShadowBlur: 1}, 7},
+    {Effects{GlowRadius: 4.5}, 6},
+    {Effects{OutlineWidth: 2, Shadow: true, ShadowX: 1, GlowRadius: 4}, 5},
+  } {
+    if got := test.effects.pad(); got != test.pad {
+      t.Errorf("%+v: pad %d, want %d", test.effects, got, test.pad)
+    }
+  }
+}
+
+// TestEffects bakes each effect alone in both storages and checks that items
+// grow by the effect padding without moving the glyph, that the padding holds
+// effect texels but no glyph texels, and that each layer reaches past the
+// glyph as it should.
+func TestEffects(t *testing.T) {
+  data := testFont(t)
+  opts := Options{FontPt: 32, Width: 256, Height: 256, Pad: 2}
+  plain := NewWithOptions(&data, []rune("O"), opts)
+  for _, storage := range []EffectStorage{EffectChannels, EffectPages} {
+    for _, test := range []struct {
+      effect  Effect
+      effects Effects
+    }{
+      {EffectOutline, Effects{OutlineWidth: 2}},
+      {EffectShadow, Effects{Shadow: true, ShadowX: 3, ShadowY: 2, ShadowBlur: 1}},
+      {EffectGlow, Effects{GlowRadius: 4}},
+    } {
+      opts.Effects = test.effects
+      opts.Effects.Storage = storage
+      pad := opts.Effects.pad()
+      atlas := NewWithOptions(&data, []rune("O"), opts)
+      item, p := atlas.Items['O'], plain.Items['O']
+      if item.Width != p.Width+2*pad || item.Height != p.Height+2*pad || item.BearingX != p.BearingX-float32(pad) || item.Descent != p.Descent+float32(pad) || item.Advance != p.Advance {
+        t.Errorf("storage %d, layer %d: item %dx%d bearing %v descent %v advance %v, want %dx%d bearing %v descent %v advance %v", storage, test.effect,
+          item.Width, item.Height, item.BearingX, item.Descent, item.Advance, p.Width+2*pad, p.Height+2*pad, p.BearingX-float32(pad), p.Descent+float32(pad), p.Advance)
+      }
+
+      w, h := item.Width, item.Height
+      // within pad texels of the item's edge
+      inPad := func(i, pad int) bool {
+        x, y := i%w, i/w
+        return x < pad || y < pad || x >= w-pad || y >= h-pad
+      }
+      fill := layerValues(t, &atlas, item, EffectFill)
+      for i, v := range fill {
+        if inPad(i, pad) && v != 0 {
+          t.Fatalf("storage %d, layer %d: glyph texel (%d, %d) in the effect padding is %v", storage, test.effect, i%w, i/w, v)
+        }
+      }
+
+      layer := layerValues(t, &atlas, item, test.effect)
+      outside, padded := 0, 0
+      var sum, sx, sy, fx, fy, fsum float64
+      for i, v := range layer {
+        if v > 0 && fill[i] == 0 {
+          outside++
+          if inPad(i, pad+opts.Pad) {
+            padded++
+          }
+        }
+        // the edge texels fade to nothing
+        x, y := i%w, i/w
+        if (x == 0 || y == 0 || x == w-1 || y == h-1) && v > 0.02 {
+          t.Errorf("storage %d: layer %d is cut off at (%d, %d), with %v", storage, test.effect, x, y, v)
+        }
+        sum += float64(v)
+        sx += float64(v) * float64(x)
+        sy += float64(v) * float64(y)
+        fsum += float64(fill[i])
+        fx += float64(fill[i]) * float64(x)
+        fy += float64(fill[i]) * float64(y)
+      }
+      if outside == 0 || padded == 0 {
+        t.Errorf("storage %d: layer %d has %d texels outside the glyph, %d in the padding", storage, test.effect, outside, padded)
+      }
+
+      switch test.effect {
+      case EffectOutline:
+        // the outline layer holds the glyph under its outline
+        for i, v := range layer {
+          if v < fill[i]-1.0/255 {
+            t.Fatalf("storage %d: outline texel (%d, %d) is %v, under the glyph's %v", storage, i%w, i/w, v, fill[i])
+          }
+        }
+      case EffectShadow:
+        // the shadow is the glyph moved by the offset
+        dx, dy := sx/sum-fx/fsum, sy/sum-fy/fsum
+        if math.Abs(dx-float64(test.effects.ShadowX)) > 0.5 || math.Abs(dy-float64(test.effects.ShadowY)) > 0.5 {
+          t.Errorf("storage %d: shadow moved by (%.2f, %.2f), want (%v, %v)", storage, dx, dy, test.effects.ShadowX, test.effects.ShadowY)
+        }
+      case EffectGlow:
+        if sum <= fsum {
+          t.Errorf("storage %d: glow of %v doesn't spread the glyph's %v", storage, sum, fsum)
+        }
+      }
+    }
+  }
+}
@@ -9,11 +9,14 @@ import (
   
   "image"
   "image/draw"
+  "image/color"
 
   "github.com/go-gl/gl/v4.1-core/gl"
   "github.com/go-gl/glfw/v3.2/glfw"
   
   "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/layout"
+  "github.com/vrav/ratlas/mesh"
 )
 
 const (
@@ -24,96 +27,20 @@ const (
 
 type TextMesh []float32
 
-func (mesh *TextMesh) AddRune(item *ratlas.AtlasItem, posX, posY, scale, r, g, b, a float32) {
-  quad := runeQuadSingleColor(item, posX + item.BearingX*scale, posY - item.Descent*scale, scale, r, g, b, a);
-  *mesh = append(*mesh, quad...)
-}
-
-func (mesh *TextMesh) TextBox(atlas *ratlas.Atlas, str string, left, top, width, height, fontPt, r, g, b, a float32) {
-  textRunes := []rune(str)
-  textLength := len(textRunes)
-  
-  scale := fontPt/float32(atlas.FontPt)
-  
-  var posX, posY float32
-  posX, posY = left, top - float32(atlas.FontPt)*scale
-  right, bottom := left+width, top-height
-  lineHeight := atlas.Height()*scale
-  
-  Outer: for i, ru := range textRunes {
-    // seek ahead to see if word requires wrapping; if so, wrap
-    if ru != ' ' && i > 0 && textRunes[i-1] == ' ' {
-      aheadPos := posX
-      for j, rr := range textRunes[i:] {
-        if rr == ' ' || rr == '\n' {
-          break
-        }
-        if item, ok := atlas.Items[rr]; ok {
-          aheadPos += item.Advance * scale
-          if j < textLength - 1 {
-            aheadPos += atlas.Kern(textRunes[j], textRunes[j+1]) * scale
-          }
-        }
-      }
-      if aheadPos > right {
-        // word will spill off edge; wrap line
-        if posY < bottom {
-          // fmt.Println("broke at wrap")
-          break Outer;
-        }
-        posY -= lineHeight
-        posX = left
-      }
-    }
-    
-    item, ok := atlas.Items[ru]
-    if !ok {
-      if ru == '\n' {
-        if posY < bottom {
-          // fmt.Println("broke at \\n")
-          break Outer;
-        }
-        posY -= lineHeight
-        posX = left
-      }
-      continue
-    }
-    
-    if ru == ' ' {
-      posX += item.Advance * scale
-      continue
-    }
-    
-    mesh.AddRune(item, posX, posY, scale, r, g, b, a)
-    
-    posX += item.Advance * scale
-    if i < textLength - 1 {
-      posX += atlas.Kern(textRunes[i], textRunes[i+1]) * scale
-    }
+func (textMesh *TextMesh) TextBox(atlas *ratlas.Atlas, markup string, left, top, width, height, fontPt, r, g, b, a float32) {
+  // markup such as "[color=#ff8000]orange[/color]" styles spans of the text
+  spans, err := layout.ParseMarkup(markup, layout.Style{}, nil)
+  if err != nil {
+    spans = []layout.Span{{Text: markup}}
   }
-}
-
-func screenX(x float32) float32 {
-  return 2.0 * (x / float32(windowWidth)) - 1.0
-}
-
-func screenY(y float32) float32 {
-  return 2.0 * (y / float32(windowHeight)) - 1.0
-}
-
-func runeQuadSingleColor(item *ratlas.AtlasItem, posX, posY, scale, r, g, b, a float32) TextMesh {
-  w, h := float32(item.Width), float32(item.Height)
+  // layout works in pixels with y growing down from the top of the box
+  text := layout.LayoutSpans(atlas, spans, layout.Options{Size: fontPt, Width: width, Height: height, Overflow: layout.OverflowEllipsisEnd})
   
-  return TextMesh{
-    // X Y   U V   scale   r g b a
-    screenX(posX), screenY(posY),                      item.PercentPosX, item.PercentPosY+item.PercentHeight, scale, r, g, b, a,
-    screenX(posX + w*scale), screenY(posY + h*scale),  item.PercentPosX+item.PercentWidth, item.PercentPosY, scale, r, g, b, a,
-    screenX(posX), screenY(posY + h*scale),            item.PercentPosX, item.PercentPosY, scale, r, g, b, a,
-    
-    screenX(posX), screenY(posY),                      item.PercentPosX, item.PercentPosY+item.PercentHeight, scale, r, g, b, a,
-    screenX(posX + w*scale), screenY(posY),            item.PercentPosX+item.PercentWidth, item.PercentPosY+item.PercentHeight, scale, r, g, b, a,
-    screenX(posX + w*scale), screenY(posY + h*scale),  item.PercentPosX+item.PercentWidth, item.PercentPosY, scale, r, g, b, a,
-  }
+  // mesh converts to GL's y-up normalized device coordinates
+  m := mesh.New(mesh.Options{Width: windowWidth, Height: windowHeight, NDC: true, YUp: true})
+  c := color.NRGBA{uint8(r*255), uint8(g*255), uint8(b*255), uint8(a*255)}
+  m.AddSpans(text, spans, left, windowHeight - top, c)
+  *textMesh = append(*textMesh, m.Floats(mesh.Position, mesh.TexCoord, mesh.Scale, mesh.Color)...)
 }
 
 // handleKeys is used as a glfw key callback
@@ -263,7 +190,7 @@ func main() {
     }
     
     flashAmount := math.Abs(math.Sin(time))
-    frameMesh.TextBox(&atlas, "Test string!", float32(mX), float32(mY), 250, 250, 150.0, 1.0, 0.8, 1.0, float32(flashAmount))
+    frameMesh.TextBox(&atlas, "Test [color=#ff8000]string[/color]!", float32(mX), float32(mY), 250, 250, 150.0, 1.0, 0.8, 1.0, float32(flashAmount))
     
     numVertices := len(frameMesh)/9
     
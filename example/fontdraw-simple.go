@@ -8,11 +8,14 @@ import (
   
   "image"
   "image/draw"
+  "image/color"
 
   "github.com/go-gl/gl/v4.1-core/gl"
   "github.com/go-gl/glfw/v3.2/glfw"
   
   "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/layout"
+  "github.com/vrav/ratlas/mesh"
 )
 
 const (
@@ -21,29 +24,6 @@ const (
   windowTitle = "fontdraw-simple"
 )
 
-func screenX(x float32) float32 {
-  return 2.0 * (x / float32(windowWidth)) - 1.0
-}
-
-func screenY(y float32) float32 {
-  return 2.0 * (y / float32(windowHeight)) - 1.0
-}
-
-func runeQuad(item *ratlas.AtlasItem, posX, posY, scale float32) []float32 {
-  w, h := float32(item.Width), float32(item.Height)
-  
-  return []float32{
-    // X Y   U V
-    screenX(posX), screenY(posY),                      item.PercentPosX, item.PercentPosY+item.PercentHeight,
-    screenX(posX + w*scale), screenY(posY + h*scale),  item.PercentPosX+item.PercentWidth, item.PercentPosY,
-    screenX(posX), screenY(posY + h*scale),            item.PercentPosX, item.PercentPosY,
-    
-    screenX(posX), screenY(posY),                      item.PercentPosX, item.PercentPosY+item.PercentHeight,
-    screenX(posX + w*scale), screenY(posY),            item.PercentPosX+item.PercentWidth, item.PercentPosY+item.PercentHeight,
-    screenX(posX + w*scale), screenY(posY + h*scale),  item.PercentPosX+item.PercentWidth, item.PercentPosY,
-  }
-}
-
 // handleKeys is used as a glfw key callback
 func handleKeys(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
 	if key == glfw.KeyEscape && action == glfw.Press {
@@ -110,54 +90,17 @@ func main() {
   
   // the important bit. generate a mesh of exampleText's characters.
   
-  var mesh []float32
-  textRunes := []rune(exampleText)
-  textLength := len(textRunes)
   leftMargin := float32(10.0)
   scale := float32(0.5)
   
-  var posX, posY float32
-  posX, posY = leftMargin, windowHeight - float32(atlas.FontPt)*scale
-  lineHeight := atlas.Height()*scale
-  
-  for i, r := range textRunes {
-    // seek ahead to see if word requires wrapping; if so, wrap
-    if r != ' ' && i > 0 && textRunes[i-1] == ' ' {
-      aheadPos := posX
-      for j, rr := range textRunes[i:] {
-        if rr == ' ' || rr == '\n' {
-          break
-        }
-        if item, ok := atlas.Items[rr]; ok {
-          aheadPos += item.Advance * scale
-          if j < textLength - 1 {
-            aheadPos += atlas.Kern(textRunes[j], textRunes[j+1]) * scale
-          }
-        }
-      }
-      if aheadPos > windowWidth {
-        // word will spill off edge of windowWidth; wrap line
-        posY -= lineHeight
-        posX = leftMargin
-      }
-    }
-    
-    item, ok := atlas.Items[r]
-    if !ok {
-      if r == '\n' {
-        posY -= lineHeight
-        posX = leftMargin
-      }
-      continue
-    }
-    
-    quad := runeQuad(item, posX + item.BearingX*scale, posY - item.Descent*scale, scale)
-    mesh = append(mesh, quad...)
-    posX += item.Advance * scale
-    if i < textLength - 1 {
-      posX += atlas.Kern(textRunes[i], textRunes[i+1]) * scale
-    }
-  }
+  text := layout.Layout(&atlas, exampleText, layout.Options{
+    Size: float32(atlas.FontPt)*scale,
+    Width: windowWidth - leftMargin,
+  })
+  // layout's y grows down from the top of the window; GL's grows up
+  m := mesh.New(mesh.Options{Width: windowWidth, Height: windowHeight, NDC: true, YUp: true})
+  m.AddText(text, leftMargin, 0, color.White)
+  vertices := m.Floats(mesh.Position, mesh.TexCoord)
   
   // loading up OpenGL.
   
@@ -189,7 +132,7 @@ func main() {
   var vbo uint32
   gl.GenBuffers(1, &vbo)
   gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
-  gl.BufferData(gl.ARRAY_BUFFER, len(mesh)*4, gl.Ptr(mesh), gl.STATIC_DRAW)
+  gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
   
   posAttrib := uint32(gl.GetAttribLocation(program, gl.Str("position\x00")))
   gl.EnableVertexAttribArray(posAttrib)
@@ -199,7 +142,7 @@ func main() {
   gl.EnableVertexAttribArray(texCoordAttrib)
   gl.VertexAttribPointer(texCoordAttrib, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(2*4))
   
-  numVertices := int32(len(mesh)/4)
+  numVertices := int32(len(vertices)/4)
   
   // enable blending
   gl.Enable(gl.BLEND)
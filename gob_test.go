@@ -0,0 +1,144 @@
+package ratlas
+
+import (
+  "bytes"
+  "encoding/gob"
+  "fmt"
+  "path/filepath"
+  "testing"
+
+  "golang.org/x/image/font"
+)
+
+// TestGobImageRoundTrip saves atlases of each pixel format as a gob and PNG
+// pages, loads the gob and then the pages into an empty atlas, and checks
+// that the pages come back in their type with the same texels.
+func TestGobImageRoundTrip(t *testing.T) {
+  data := testFont(t)
+  dir := t.TempDir()
+  for _, test := range []struct {
+    format  PixelFormat
+    effects Effects
+    page    string
+  }{
+    {FormatGray, Effects{}, "*image.Gray"},
+    {FormatAlpha, Effects{}, "*image.Alpha"},
+    {FormatRGBA, Effects{}, "*image.RGBA"},
+    {FormatGray16, Effects{}, "*image.Gray16"},
+    {FormatGray16, Effects{OutlineWidth: 2, Storage: EffectPages}, "*image.Gray16"},
+    {FormatAlpha, Effects{OutlineWidth: 2, Storage: EffectChannels}, "*image.NRGBA"},
+  } {
+    name := fmt.Sprintf("format %d, effects %+v", test.format, test.effects)
+    atlas := NewWithOptions(&data, []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"), Options{FontPt: 24, Width: 128, Height: 64, Pad: 4, Format: test.format, Effects: test.effects})
+    base := filepath.Join(dir, fmt.Sprintf("atlas-%d-%d", test.format, test.effects.Storage))
+    if err := atlas.SaveGobFile(base + ".gob"); err != nil {
+      t.Fatal(err)
+    }
+    if err := atlas.SaveImageFiles(base); err != nil {
+      t.Fatal(err)
+    }
+    var fileNames []string
+    for i := range atlas.Images {
+      fileNames = append(fileNames, fmt.Sprintf("%s-%d.png", base, i))
+    }
+
+    var loaded Atlas
+    if err := loaded.LoadGobFile(base + ".gob"); err != nil {
+      t.Fatal(err)
+    }
+    if err := loaded.LoadImageFiles(fileNames); err != nil {
+      t.Fatal(err)
+    }
+    if loaded.Format != test.format || len(loaded.Images) != len(atlas.Images) {
+      t.Fatalf("%s: loaded format %d with %d pages, want %d with %d", name, loaded.Format, len(loaded.Images), test.format, len(atlas.Images))
+    }
+    for r, item := range atlas.Items {
+      l := loaded.Items[r]
+      if l == nil || l.Node == nil || l.ImageIndex != item.ImageIndex || itemRect(l) != itemRect(item) || uvs(l) != uvs(item) {
+        t.Errorf("%s: %q isn't loaded where it was packed", name, r)
+      }
+    }
+
+    fine := false
+    for i, img := range atlas.Images {
+      if got := fmt.Sprintf("%T", loaded.Images[i]); got != test.page {
+        t.Errorf("%s: page %d loaded as %s, want %s", name, i, got, test.page)
+        continue
+      }
+      want, got := pagePlane(img, 4), pagePlane(loaded.Images[i], 4)
+      if len(got.pix) != len(want.pix) {
+        t.Fatalf("%s: page %d is %v, want %v", name, i, loaded.Images[i].Bounds(), img.Bounds())
+      }
+      for k, v := range want.pix {
+        if got.pix[k] != v {
+          t.Errorf("%s: page %d channel %d of texel %d is %d, want %d", name, i, k%4, k/4, got.pix[k], v)
+          break
+        }
+        // 16 bits of coverage, not 8 widened
+        fine = fine || v%0x101 != 0
+      }
+    }
+    if test.format == FormatGray16 && !fine {
+      t.Errorf("%s: pages hold only 8 bit coverage", name)
+    }
+  }
+}
+
+// TestGobFaceOptions checks that FaceOptions survive a gob round trip, and
+// that gobs from before they were saved load with the defaults.
+func TestGobFaceOptions(t *testing.T) {
+  data := testFont(t)
+  faceOpts := FaceOptions{DPI: 96, Hinting: font.HintingFull, SubPixelsX: 8, SubPixelsY: 2, GlyphCacheEntries: 64}
+  atlas := NewWithOptions(&data, []rune("Ag"), Options{FontPt: 18, Width: 64, Height: 64, Pad: 1, Embolden: 1, FaceOptions: faceOpts})
+  b, err := atlas.createGob()
+  if err != nil {
+    t.Fatal(err)
+  }
+  var loaded Atlas
+  if err := loaded.readGob(b); err != nil {
+    t.Fatal(err)
+  }
+  if loaded.FaceOptions != faceOpts {
+    t.Errorf("FaceOptions loaded as %+v, want %+v", loaded.FaceOptions, faceOpts)
+  }
+  if err := loaded.ReloadFont(&data); err != nil {
+    t.Fatal(err)
+  }
+  if loaded.PixelsPerEm() != 24 || loaded.PixelsPerEm() != atlas.PixelsPerEm() {
+    t.Errorf("%v pixels per em, want 24", loaded.PixelsPerEm())
+  }
+  a, _ := atlas.Face.GlyphAdvance('A')
+  if l, _ := loaded.Face.GlyphAdvance('A'); l != a {
+    t.Errorf("reloaded face advances A by %v, want %v", l, a)
+  }
+
+  // gobs of the original format, and of the one just before FaceOptions
+  for _, fields := range [][]interface{}{
+    {atlas.FontPt, atlas.Pad, atlas.Items},
+    {atlas.FontPt, atlas.Pad, atlas.Items, atlas.glyphOnlyItems(), atlas.Effects, []float32{atlas.Embolden, atlas.Oblique}},
+  } {
+    buf := new(bytes.Buffer)
+    enc := gob.NewEncoder(buf)
+    for _, field := range fields {
+      if err := enc.Encode(field); err != nil {
+        t.Fatal(err)
+      }
+    }
+    var old Atlas
+    if err := old.GobDecode(buf.Bytes()); err != nil {
+      t.Fatalf("%d fields: %v", len(fields), err)
+    }
+    if old.FaceOptions != (FaceOptions{}) || old.FontPt != atlas.FontPt || len(old.Items) != len(atlas.Items) {
+      t.Errorf("%d fields: loaded FaceOptions %+v, FontPt %v and %d items", len(fields), old.FaceOptions, old.FontPt, len(old.Items))
+    }
+    if len(fields) > 3 && old.Embolden != atlas.Embolden {
+      t.Errorf("%d fields: Embolden is %v, want %v", len(fields), old.Embolden, atlas.Embolden)
+    }
+    if err := old.ReloadFont(&data); err != nil {
+      t.Fatal(err)
+    }
+    if old.PixelsPerEm() != float32(atlas.FontPt) {
+      t.Errorf("%d fields: %v pixels per em at the default DPI, want %v", len(fields), old.PixelsPerEm(), atlas.FontPt)
+    }
+  }
+}
@@ -0,0 +1,155 @@
+// Package grapheme finds extended grapheme cluster boundaries following
+// Unicode Text Segmentation (UAX #29), so that carets move over user
+// perceived characters such as "é" written with a combining accent, emoji
+// ZWJ sequences and flags.
+package grapheme
+
+import "sort"
+
+// property packs a Grapheme_Cluster_Break value with the Extended_Pictographic
+// and Indic_Conjunct_Break properties.
+type property uint8
+
+const (
+  other property = iota
+  cr
+  control
+  extend
+  l
+  lf
+  lv
+  lvt
+  prepend
+  regionalIndicator
+  spacingMark
+  t
+  v
+  zwj
+)
+
+const (
+  pictographic property = 1 << 4
+
+  // Indic_Conjunct_Break values, in bits 5 and 6
+  linker         property = 1 << 5
+  consonant      property = 2 << 5
+  conjunctExtend property = 3 << 5
+)
+
+func (p property) class() property {
+  return p & 15
+}
+
+func (p property) conjunct() property {
+  return p & (3 << 5)
+}
+
+type propertyRange struct {
+  lo, hi rune
+  p      property
+}
+
+func lookup(r rune) property {
+  i := sort.Search(len(propertyRanges), func(i int) bool { return propertyRanges[i].hi >= r })
+  if i < len(propertyRanges) && propertyRanges[i].lo <= r {
+    return propertyRanges[i].p
+  }
+  return other
+}
+
+// Boundaries reports, for each index of runes and the end of text, whether a
+// grapheme cluster starts there. The start and end of text are boundaries.
+func Boundaries(runes []rune) []bool {
+  n := len(runes)
+  props := make([]property, n)
+  for i, r := range runes {
+    props[i] = lookup(r)
+  }
+  bounds := make([]bool, n+1)
+  bounds[0], bounds[n] = true, true
+
+  // state for GB9c, GB11 and GB12/13, describing the runes before i
+  var conjunct, linked bool
+  emoji := false
+  ri := 0
+  for i := 1; i < n; i++ {
+    a, b := props[i-1], props[i]
+    switch {
+    case a.conjunct() == consonant:
+      conjunct, linked = true, false
+    case a.conjunct() == linker && conjunct:
+      linked = true
+    case a.conjunct() == conjunctExtend && conjunct:
+    default:
+      conjunct, linked = false, false
+    }
+    switch {
+    case a&pictographic != 0:
+      emoji = true
+    case a.class() == extend && emoji:
+    case a.class() == zwj && emoji:
+    default:
+      emoji = false
+    }
+    if a.class() == regionalIndicator {
+      ri++
+    } else {
+      ri = 0
+    }
+    bounds[i] = boundary(a, b, conjunct && linked, emoji, ri)
+  }
+  return bounds
+}
+
+// boundary applies the rules between two runes with properties a and b.
+// linked is set after a consonant and a linker (GB9c), emoji after an
+// Extended_Pictographic and Extends (GB11), and ri counts the regional
+// indicators ending at a.
+func boundary(a, b property, linked, emoji bool, ri int) bool {
+  ac, bc := a.class(), b.class()
+  switch {
+  case ac == cr && bc == lf: // GB3
+    return false
+  case ac == cr || ac == lf || ac == control: // GB4
+    return true
+  case bc == cr || bc == lf || bc == control: // GB5
+    return true
+  case ac == l && (bc == l || bc == v || bc == lv || bc == lvt): // GB6
+    return false
+  case (ac == lv || ac == v) && (bc == v || bc == t): // GB7
+    return false
+  case (ac == lvt || ac == t) && bc == t: // GB8
+    return false
+  case bc == extend || bc == zwj || bc == spacingMark || ac == prepend: // GB9, GB9a, GB9b
+    return false
+  case linked && b.conjunct() == consonant: // GB9c
+    return false
+  case ac == zwj && emoji && b&pictographic != 0: // GB11
+    return false
+  case ac == regionalIndicator && bc == regionalIndicator && ri%2 == 1: // GB12, GB13
+    return false
+  }
+  return true
+}
+
+// Next returns the start of the grapheme cluster after the one holding runes[i].
+func Next(runes []rune, i int) int {
+  if i >= len(runes) {
+    return len(runes)
+  }
+  bounds := Boundaries(runes)
+  for i++; !bounds[i]; i++ {
+  }
+  return i
+}
+
+// Prev returns the start of the grapheme cluster before index i.
+func Prev(runes []rune, i int) int {
+  if i <= 0 {
+    return 0
+  }
+  bounds := Boundaries(runes)
+  for i--; !bounds[i]; i-- {
+  }
+  return i
+}
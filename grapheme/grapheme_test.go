@@ -0,0 +1,58 @@
+package grapheme
+
+import (
+  "bufio"
+  "os"
+  "strconv"
+  "strings"
+  "testing"
+)
+
+// TestConformance runs GraphemeBreakTest.txt of the Unicode version of the
+// tables: "÷" marks a boundary and "×" none, between hexadecimal runes.
+func TestConformance(t *testing.T) {
+  f, err := os.Open("testdata/GraphemeBreakTest.txt")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer f.Close()
+  scanner := bufio.NewScanner(f)
+  tests := 0
+  for n := 1; scanner.Scan(); n++ {
+    line := scanner.Text()
+    if i := strings.IndexByte(line, '#'); i >= 0 {
+      line = line[:i]
+    }
+    fields := strings.Fields(line)
+    if len(fields) == 0 {
+      continue
+    }
+    var runes []rune
+    var want []bool
+    for _, field := range fields {
+      switch field {
+      case "÷", "×":
+        want = append(want, field == "÷")
+      default:
+        v, err := strconv.ParseUint(field, 16, 32)
+        if err != nil {
+          t.Fatalf("line %d: %v", n, err)
+        }
+        runes = append(runes, rune(v))
+      }
+    }
+    got := Boundaries(runes)
+    for i := range want {
+      if got[i] != want[i] {
+        t.Errorf("line %d: %s: boundary before rune %d is %v", n, strings.TrimSpace(line), i, got[i])
+      }
+    }
+    tests++
+  }
+  if err := scanner.Err(); err != nil {
+    t.Fatal(err)
+  }
+  if tests == 0 {
+    t.Fatal("no tests")
+  }
+}
@@ -0,0 +1,156 @@
+package ratlas
+
+import (
+  "bytes"
+  "encoding/binary"
+  "fmt"
+  "math"
+)
+
+// ktx2Identifier starts every KTX2 file.
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// vkFormat returns the format's VkFormat, as KTX2 files record it.
+func (format TextureFormat) vkFormat() uint32 {
+  switch format {
+  case TextureRG8:
+    return 16 // VK_FORMAT_R8G8_UNORM
+  case TextureRGBA8:
+    return 37 // VK_FORMAT_R8G8B8A8_UNORM
+  case TextureR16:
+    return 70 // VK_FORMAT_R16_UNORM
+  case TextureBC4:
+    return 139 // VK_FORMAT_BC4_UNORM_BLOCK
+  case TextureBC5:
+    return 141 // VK_FORMAT_BC5_UNORM_BLOCK
+  case TextureEACR11:
+    return 153 // VK_FORMAT_EAC_R11_UNORM_BLOCK
+  }
+  return 9 // VK_FORMAT_R8_UNORM
+}
+
+// SaveKTX2Files saves pages to KTX2 files, name-0.ktx2 and so on, or
+// name.ktx2 for an array texture.
+func (atlas *Atlas) SaveKTX2Files(name string, opts TextureOptions) error {
+  return atlas.saveTextureFiles(name, ".ktx2", opts, func(layers [][][]byte, w, h int, array bool) []byte {
+    return encodeKTX2(opts.Format, layers, w, h, array)
+  })
+}
+
+// LoadKTX2Files loads pages from the top mip levels of KTX2 files, in the
+// formats SaveKTX2Files writes, one per layer of array textures. Coverage
+// pages are converted to the atlas' Format, so load its gob first.
+func (atlas *Atlas) LoadKTX2Files(fileNames []string) error {
+  return atlas.loadTextureFiles(fileNames, decodeKTX2)
+}
+
+// encodeKTX2 returns a KTX2 file of layers, each a mip chain.
+func encodeKTX2(format TextureFormat, layers [][][]byte, w, h int, array bool) []byte {
+  levels := len(layers[0])
+  layerCount := 0
+  if array {
+    layerCount = len(layers)
+  }
+
+  // data format descriptor, one sample per channel
+  channels := format.channels()
+  dfd := new(bytes.Buffer)
+  blockSize := 24 + 16*channels
+  put(dfd, uint32(4+blockSize), uint32(0), uint16(2), uint16(blockSize))
+  typeSize, align := 1, 4
+  if size := format.blockSize(); size > 0 {
+    // BC4, BC5 or ETC2 color model of 4x4 blocks, linear transfer, with a
+    // 64 bit sample per channel
+    model := map[TextureFormat]uint8{TextureBC4: 131, TextureBC5: 132, TextureEACR11: 161}[format]
+    put(dfd, []byte{model, 1, 1, 0}, [4]byte{3, 3}, [8]byte{uint8(size)})
+    for c := 0; c < channels; c++ {
+      put(dfd, uint16(64*c), uint8(63), uint8(c), [4]byte{}, uint32(0), uint32(math.MaxUint32))
+    }
+    align = size
+  } else {
+    // RGBSDA color model, BT.709 primaries, linear transfer, straight alpha
+    bits := 8 * format.texelSize() / channels
+    put(dfd, []byte{1, 1, 1, 0}, [4]byte{}, [8]byte{uint8(format.texelSize())})
+    for c := 0; c < channels; c++ {
+      id := uint8(c)
+      if c == 3 {
+        id = 15 // alpha
+      }
+      put(dfd, uint16(c*bits), uint8(bits-1), id, [4]byte{}, uint32(0), uint32(1)<<uint(bits)-1)
+    }
+    typeSize = bits / 8
+  }
+
+  // levels follow the descriptor, smallest first, each all layers
+  dfdOffset := 80 + 24*levels
+  offset := dfdOffset + dfd.Len()
+  offsets := make([]int, levels)
+  for l := levels - 1; l >= 0; l-- {
+    offset = (offset + align - 1) / align * align
+    offsets[l] = offset
+    offset += len(layers[0][l]) * len(layers)
+  }
+
+  b := new(bytes.Buffer)
+  b.Write(ktx2Identifier)
+  put(b, format.vkFormat(), uint32(typeSize), uint32(w), uint32(h), uint32(0),
+    uint32(layerCount), uint32(1), uint32(levels), uint32(0))
+  put(b, uint32(dfdOffset), uint32(dfd.Len()), uint32(0), uint32(0), uint64(0), uint64(0))
+  for l := 0; l < levels; l++ {
+    size := uint64(len(layers[0][l]) * len(layers))
+    put(b, uint64(offsets[l]), size, size)
+  }
+  b.Write(dfd.Bytes())
+  for l := levels - 1; l >= 0; l-- {
+    b.Write(make([]byte, offsets[l]-b.Len()))
+    for _, layer := range layers {
+      b.Write(layer[l])
+    }
+  }
+  return b.Bytes()
+}
+
+// decodeKTX2 returns the top level of each layer of a KTX2 file.
+func decodeKTX2(b []byte) (*textureFile, error) {
+  if len(b) < 104 || !bytes.Equal(b[:12], ktx2Identifier) {
+    return nil, fmt.Errorf("not a KTX2 file")
+  }
+  le := binary.LittleEndian
+  vkFormat, w, h := le.Uint32(b[12:]), int(le.Uint32(b[20:])), int(le.Uint32(b[24:]))
+  depth, layers, faces, scheme := le.Uint32(b[28:]), int(le.Uint32(b[32:])), le.Uint32(b[36:]), le.Uint32(b[44:])
+  if depth > 0 || faces != 1 || scheme != 0 {
+    return nil, fmt.Errorf("only 2D textures without supercompression are supported")
+  }
+  file := &textureFile{w: w, h: h}
+  found := false
+  for _, format := range textureFormats {
+    if format.vkFormat() == vkFormat {
+      file.format, found = format, true
+    }
+  }
+  if !found {
+    return nil, fmt.Errorf("unsupported VkFormat %d", vkFormat)
+  }
+  if layers == 0 {
+    layers = 1
+  }
+
+  // the level index starts with the top level
+  offset, length := le.Uint64(b[80:]), le.Uint64(b[88:])
+  size := file.format.levelSize(w, h)
+  if length != uint64(size*layers) || offset+length > uint64(len(b)) {
+    return nil, fmt.Errorf("top level is %d bytes, not %d", length, size*layers)
+  }
+  for i := 0; i < layers; i++ {
+    start := int(offset) + i*size
+    file.layers = append(file.layers, b[start:start+size])
+  }
+  return file, nil
+}
+
+// put writes values little-endian to b.
+func put(b *bytes.Buffer, values ...interface{}) {
+  for _, v := range values {
+    binary.Write(b, binary.LittleEndian, v)
+  }
+}
@@ -0,0 +1,168 @@
+package layout
+
+import (
+  "github.com/vrav/ratlas/linebreak"
+)
+
+// Align is the horizontal alignment of lines.
+type Align int
+
+const (
+  // AlignStart aligns lines to the left in left-to-right paragraphs and to
+  // the right in right-to-left ones.
+  AlignStart Align = iota
+  AlignEnd
+  AlignLeft
+  AlignRight
+  AlignCenter
+
+  // AlignJustify stretches lines to fill the width, except for the last line
+  // of a paragraph and lines containing tabs, which are aligned to the start.
+  AlignJustify
+)
+
+// VAlign is the vertical alignment of text in the box.
+type VAlign int
+
+const (
+  AlignTop VAlign = iota
+  AlignMiddle
+  AlignBottom
+)
+
+// lineInfo is what align needs to know about a line beyond Line.
+type lineInfo struct {
+  // gaps is the number of justification opportunities in the line, and
+  // glyphGaps the number preceding each glyph from the line's start edge.
+  gaps      int
+  glyphGaps []int
+
+  // last is set for lines ending a paragraph or at a mandatory break, and
+  // tabbed for lines containing tabs.
+  last   bool
+  tabbed bool
+}
+
+// nextTab returns the position of the first tab stop after x.
+func (l *layouter) nextTab(x float32) float32 {
+  for _, stop := range l.opts.TabStops {
+    if stop > x {
+      return stop
+    }
+  }
+  var last float32
+  if n := len(l.opts.TabStops); n > 0 {
+    last = l.opts.TabStops[n-1]
+  }
+  steps := int((x-last)/l.opts.TabWidth) + 1
+  return last + float32(steps)*l.opts.TabWidth
+}
+
+// ideographic reports whether a justified line may stretch between the
+// adjacent runes a and b.
+func ideographic(a, b rune) bool {
+  if a == ' ' || b == ' ' {
+    return false
+  }
+  for _, r := range []rune{a, b} {
+    switch linebreak.ClassOf(r) {
+    case linebreak.ID, linebreak.CJ, linebreak.H2, linebreak.H3, linebreak.JL, linebreak.JV, linebreak.JT:
+      return true
+    }
+  }
+  return false
+}
+
+// align moves the placed lines into position within the box.
+func (l *layouter) align() {
+  r := l.result
+  width := l.opts.Width
+  if width <= 0 {
+    for _, line := range r.Lines {
+      if line.Width > width {
+        width = line.Width
+      }
+    }
+  }
+
+  r.Width = 0
+  for i := range r.Lines {
+    line := &r.Lines[i]
+    info := l.lines[i]
+    rtl := line.Level&1 == 1
+
+    align := l.opts.Align
+    if align == AlignJustify && (info.last || info.tabbed || info.gaps == 0 || line.Width >= width) {
+      align = AlignStart
+    }
+    switch align {
+    case AlignStart:
+      if rtl {
+        align = AlignRight
+      } else {
+        align = AlignLeft
+      }
+    case AlignEnd:
+      if rtl {
+        align = AlignLeft
+      } else {
+        align = AlignRight
+      }
+    }
+
+    var offset float32
+    switch align {
+    case AlignRight:
+      offset = width - line.Width
+    case AlignCenter:
+      offset = (width - line.Width) / 2
+    case AlignJustify:
+      // glyphs and rune edges shift by the gaps between them and the
+      // line's start edge
+      extra := (width - line.Width) / float32(info.gaps)
+      shift := func(gaps int) float32 {
+        if rtl {
+          gaps = info.gaps - gaps
+        }
+        return float32(gaps) * extra
+      }
+      for k := line.GlyphStart; k < line.GlyphEnd; k++ {
+        r.Glyphs[k].X += shift(info.glyphGaps[k-line.GlyphStart])
+      }
+      for k := line.Start; k < line.End; k++ {
+        r.edges[k].left += shift(l.edgeGaps[k][0])
+        r.edges[k].right += shift(l.edgeGaps[k][1])
+      }
+      line.Width = width
+    }
+
+    line.X = offset
+    for k := line.GlyphStart; k < line.GlyphEnd; k++ {
+      r.Glyphs[k].X += offset
+    }
+    for k := line.Start; k < line.End; k++ {
+      r.edges[k].left += offset
+      r.edges[k].right += offset
+    }
+    if right := line.X + line.Width; right > r.Width {
+      r.Width = right
+    }
+  }
+
+  if len(r.Lines) > 0 {
+    r.Height = r.Lines[len(r.Lines)-1].Bottom()
+  }
+  if l.opts.Height <= 0 || l.opts.VAlign == AlignTop || r.Height >= l.opts.Height {
+    return
+  }
+  dy := l.opts.Height - r.Height
+  if l.opts.VAlign == AlignMiddle {
+    dy /= 2
+  }
+  for i := range r.Lines {
+    r.Lines[i].Y += dy
+  }
+  for i := range r.Glyphs {
+    r.Glyphs[i].Y += dy
+  }
+}
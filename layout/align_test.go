@@ -0,0 +1,90 @@
+package layout
+
+import "testing"
+
+func TestAlign(t *testing.T) {
+  atlas := testAtlas(t)
+  const width = 300
+  natural := Layout(atlas, "hello", Options{Size: 16}).Lines[0].Width
+  for _, test := range []struct {
+    align Align
+    x     float32
+  }{
+    {AlignStart, 0},
+    {AlignLeft, 0},
+    {AlignEnd, width - natural},
+    {AlignRight, width - natural},
+    {AlignCenter, (width - natural) / 2},
+  } {
+    r := Layout(atlas, "hello", Options{Size: 16, Width: width, Align: test.align})
+    if line := r.Lines[0]; !near(line.X, test.x) || !near(line.Width, natural) {
+      t.Errorf("align %d: line at %v, %v wide, want %v, %v wide", test.align, line.X, line.Width, test.x, natural)
+    }
+    if g := r.Glyphs[0]; !near(g.X, test.x) {
+      t.Errorf("align %d: first glyph at %v, want %v", test.align, g.X, test.x)
+    }
+  }
+
+  for _, test := range []struct {
+    valign VAlign
+    top    float32
+  }{
+    {AlignTop, 0},
+    {AlignMiddle, 50},
+    {AlignBottom, 100},
+  } {
+    r := Layout(atlas, "hello", Options{Size: 16, Width: width, Height: 100 + Layout(atlas, "hello", Options{Size: 16}).Height, VAlign: test.valign})
+    if top := r.Lines[0].Top(); !near(top, test.top) {
+      t.Errorf("valign %d: top at %v, want %v", test.valign, top, test.top)
+    }
+  }
+}
+
+func TestJustify(t *testing.T) {
+  atlas := testAtlas(t)
+  const width = 200
+  r := Layout(atlas, testText, Options{Size: 16, Width: width, Align: AlignJustify})
+  plain := Layout(atlas, testText, Options{Size: 16, Width: width})
+  if len(r.Lines) != len(plain.Lines) {
+    t.Fatalf("justified text has %d lines, not %d", len(r.Lines), len(plain.Lines))
+  }
+  for i, line := range r.Lines {
+    want := plain.Lines[i].Width
+    if i < len(r.Lines)-1 {
+      want = width
+    }
+    if !near(line.X, 0) || !near(line.Width, want) {
+      t.Errorf("line %d %q at %v, %v wide, want 0, %v wide", i, lineText(testText, line), line.X, line.Width, want)
+    }
+  }
+
+  // the last line of each paragraph keeps its natural width
+  r = Layout(atlas, "fit in one line\nand here", Options{Size: 16, Width: width, Align: AlignJustify})
+  for i, line := range r.Lines {
+    if line.Width >= width-1 {
+      t.Errorf("paragraph end line %d %q stretched to %v", i, lineText("fit in one line\nand here", line), line.Width)
+    }
+  }
+}
+
+func TestTabs(t *testing.T) {
+  atlas := testAtlas(t)
+  space := Layout(atlas, " ", Options{Size: 16}).Glyphs[0].Item.Advance * atlas.Scale(16)
+  for _, test := range []struct {
+    text  string
+    opts  Options
+    index int
+    x     float32
+  }{
+    {"a\tb", Options{Size: 16, TabStops: []float32{100}}, 2, 100},
+    {"a\tb\tc", Options{Size: 16, TabStops: []float32{100, 150}}, 4, 150},
+    {"a\tb\tc", Options{Size: 16, TabStops: []float32{100}, TabWidth: 30}, 4, 130},
+    {"\tb", Options{Size: 16, TabWidth: 40}, 1, 40},
+    {"a\tb", Options{Size: 16}, 2, 8 * space},
+  } {
+    r := Layout(atlas, test.text, test.opts)
+    if g := glyphAt(t, r, test.index); !near(g.X, test.x) {
+      t.Errorf("%q: rune %d at %v, want %v", test.text, test.index, g.X, test.x)
+    }
+  }
+}
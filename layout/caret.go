@@ -0,0 +1,228 @@
+package layout
+
+import (
+  "sort"
+
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/grapheme"
+)
+
+// edge is the horizontal extent of a laid out rune, which is empty for runes
+// that aren't drawn.
+type edge struct {
+  left, right float32
+  level       uint8
+}
+
+// leading returns the x of the edge a caret before the rune sits at.
+func (e edge) leading() float32 {
+  if e.level&1 == 1 {
+    return e.right
+  }
+  return e.left
+}
+
+// trailing returns the x of the edge a caret after the rune sits at.
+func (e edge) trailing() float32 {
+  if e.level&1 == 1 {
+    return e.left
+  }
+  return e.right
+}
+
+// Affinity tells which rune a caret index belongs to where that matters, as
+// at the end of a wrapped line, which is also the start of the next, or
+// between runs of different direction.
+type Affinity int
+
+const (
+  // Leading carets belong with the rune at Index, before which they sit.
+  Leading Affinity = iota
+
+  // Trailing carets belong with the rune before Index, after which they sit.
+  Trailing
+)
+
+// Caret is a caret position between runes of []rune(text).
+type Caret struct {
+  Index    int
+  Affinity Affinity
+}
+
+// NextCaret returns the index of the next grapheme cluster boundary after
+// index, so that carets step over combining marks, emoji sequences and "\r\n".
+func (r *Result) NextCaret(index int) int {
+  return grapheme.Next(r.runes, index)
+}
+
+// PrevCaret returns the index of the grapheme cluster boundary before index.
+func (r *Result) PrevCaret(index int) int {
+  return grapheme.Prev(r.runes, index)
+}
+
+// CaretLine returns the index in Lines of the line a caret is on, or -1 if
+// there are no lines.
+func (r *Result) CaretLine(c Caret) int {
+  for i, line := range r.Lines {
+    if c.Affinity == Trailing && line.Start < c.Index && c.Index <= line.End ||
+      c.Affinity == Leading && line.Start <= c.Index && c.Index < line.End {
+      return i
+    }
+  }
+  // the end of a line that isn't wrapped, or an empty line
+  for i, line := range r.Lines {
+    if line.Start <= c.Index && c.Index <= line.End {
+      return i
+    }
+  }
+  return len(r.Lines) - 1
+}
+
+// caretX returns the x of a caret on line i.
+func (r *Result) caretX(c Caret, i int) float32 {
+  line := r.Lines[i]
+  switch {
+  case c.Affinity == Trailing && c.Index > line.Start && c.Index <= line.End:
+    return r.edges[c.Index-1].trailing()
+  case c.Index >= line.Start && c.Index < line.End:
+    return r.edges[c.Index].leading()
+  case c.Index > line.Start && c.Index <= line.End:
+    return r.edges[c.Index-1].trailing()
+  case line.Level&1 == 1:
+    return line.X + line.Width
+  }
+  return line.X
+}
+
+// CaretRect returns the rectangle of a caret of the given width, centered on
+// its position and spanning the height of its line.
+func (r *Result) CaretRect(c Caret, width float32) ratlas.Bounds {
+  i := r.CaretLine(c)
+  if i < 0 {
+    return ratlas.Bounds{}
+  }
+  x := r.caretX(c, i)
+  return ratlas.Bounds{
+    X0: x - width/2,
+    Y0: r.Lines[i].Top(),
+    X1: x + width/2,
+    Y1: r.Lines[i].Bottom(),
+  }
+}
+
+// HitTest returns the caret closest to the point (x, y): on the line at y,
+// or the nearest line, at the grapheme cluster edge nearest to x.
+func (r *Result) HitTest(x, y float32) Caret {
+  if len(r.Lines) == 0 {
+    return Caret{}
+  }
+  // line boxes can overlap, so lines meet halfway between them
+  li := len(r.Lines) - 1
+  for i := 0; i+1 < len(r.Lines); i++ {
+    if y < (r.Lines[i].Bottom()+r.Lines[i+1].Top())/2 {
+      li = i
+      break
+    }
+  }
+  line := r.Lines[li]
+  if line.Start == line.End {
+    return Caret{Index: line.Start}
+  }
+
+  bounds := grapheme.Boundaries(r.runes[line.Start:line.End])
+  best := Caret{Index: line.Start}
+  bestDistance := float32(-1)
+  for a := line.Start; a < line.End; {
+    b := a + 1
+    for !bounds[b-line.Start] {
+      b++
+    }
+    left, right := r.edges[a].left, r.edges[a].right
+    for k := a + 1; k < b; k++ {
+      if r.edges[k].left < left {
+        left = r.edges[k].left
+      }
+      if r.edges[k].right > right {
+        right = r.edges[k].right
+      }
+    }
+
+    // the cluster's leading and trailing edges, as carets
+    before := Caret{Index: a, Affinity: Leading}
+    after := Caret{Index: b, Affinity: Trailing}
+    if r.edges[a].level&1 == 1 {
+      before, after = after, before
+    }
+    for _, c := range []struct {
+      x     float32
+      caret Caret
+    }{{left, before}, {right, after}} {
+      d := c.x - x
+      if d < 0 {
+        d = -d
+      }
+      if bestDistance < 0 || d <= bestDistance {
+        best, bestDistance = c.caret, d
+      }
+    }
+    a = b
+  }
+  return best
+}
+
+// SelectionRects returns the rectangles covering the runes [start, end),
+// one or more per line; runs of different direction can split a line's
+// selection into several rectangles.
+func (r *Result) SelectionRects(start, end int) []ratlas.Bounds {
+  var rects []ratlas.Bounds
+  for _, line := range r.Lines {
+    from, to := start, end
+    if from < line.Start {
+      from = line.Start
+    }
+    if to > line.End {
+      to = line.End
+    }
+    if from >= to {
+      continue
+    }
+
+    var spans [][2]float32
+    for k := from; k < to; k++ {
+      e := r.edges[k]
+      if e.right > e.left {
+        spans = append(spans, [2]float32{e.left, e.right})
+      }
+    }
+    sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+    for i, span := range spans {
+      last := len(rects) - 1
+      if i > 0 && span[0] <= rects[last].X1+0.5 {
+        if span[1] > rects[last].X1 {
+          rects[last].X1 = span[1]
+        }
+        continue
+      }
+      rects = append(rects, ratlas.Bounds{X0: span[0], Y0: line.Top(), X1: span[1], Y1: line.Bottom()})
+    }
+  }
+  return rects
+}
+
+// MoveLines returns the caret n lines below c (above, for negative n), at the
+// horizontal position x, which is usually where the caret was when vertical
+// movement started. It stays on the first or last line at the ends.
+func (r *Result) MoveLines(c Caret, n int, x float32) Caret {
+  i := r.CaretLine(c) + n
+  if i < 0 {
+    i = 0
+  }
+  if i >= len(r.Lines) {
+    i = len(r.Lines) - 1
+  }
+  if i < 0 {
+    return c
+  }
+  line := r.Lines[i]
+  return r.HitTest(x, (line.Top()+line.Bottom())/2)
+}
@@ -0,0 +1,48 @@
+package layout
+
+import "testing"
+
+func TestCaret(t *testing.T) {
+  atlas := testAtlas(t)
+  const text = "hello world"
+  r := Layout(atlas, text, Options{Size: 16})
+  n := len([]rune(text))
+  for i := 0; i <= n; i++ {
+    x := r.Lines[0].X + r.Lines[0].Width
+    if i < n {
+      x = glyphAt(t, r, i).X
+    }
+    rect := r.CaretRect(Caret{Index: i}, 2)
+    if !near((rect.X0+rect.X1)/2, x) || !near(rect.Y0, r.Lines[0].Top()) || !near(rect.Y1, r.Lines[0].Bottom()) {
+      t.Errorf("caret %d: %+v, want x %v", i, rect, x)
+    }
+    // a click on the caret finds it again
+    if c := r.HitTest(x+0.5, r.Lines[0].Y); c.Index != i {
+      t.Errorf("hit test at caret %d found %d", i, c.Index)
+    }
+  }
+  if c := r.HitTest(-50, 0); c.Index != 0 {
+    t.Errorf("hit test left of the text found %d", c.Index)
+  }
+  if c := r.HitTest(1000, 0); c.Index != n {
+    t.Errorf("hit test right of the text found %d", c.Index)
+  }
+
+  // wrapped lines
+  r = Layout(atlas, testText, Options{Size: 16, Width: 150})
+  for i, line := range r.Lines {
+    g := glyphAt(t, r, line.Start)
+    if c := r.HitTest(g.X+0.5, line.Y); c.Index != line.Start {
+      t.Errorf("hit test at the start of line %d found %d, not %d", i, c.Index, line.Start)
+    }
+    if got := r.CaretLine(Caret{Index: line.Start}); got != i {
+      t.Errorf("caret at the start of line %d is on line %d", i, got)
+    }
+  }
+  if c := r.MoveLines(Caret{Index: 0}, 1, 0); c.Index != r.Lines[1].Start {
+    t.Errorf("moved down a line to %d, not %d", c.Index, r.Lines[1].Start)
+  }
+  if r.NextCaret(0) != 1 || r.PrevCaret(1) != 0 {
+    t.Errorf("next caret %d, previous %d", r.NextCaret(0), r.PrevCaret(1))
+  }
+}
@@ -0,0 +1,682 @@
+// Package layout positions text using the metrics of a ratlas.Atlas, producing
+// glyph positions that reference AtlasItems, line boxes and overflow
+// information. It has no rendering dependency.
+//
+// Coordinates are in pixels with Y growing downwards from the top left corner
+// of the layout box. A Glyph's X and Y give its pen position on the baseline;
+// its quad is returned by Glyph.Quad.
+package layout
+
+import (
+  "unicode"
+
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/bidi"
+  "github.com/vrav/ratlas/linebreak"
+)
+
+// Glyph is a positioned rune.
+type Glyph struct {
+  Item *ratlas.AtlasItem
+
+  // Rune is the rune drawn, which is the mirrored form of the source rune
+  // (such as ')' for '(') within right-to-left runs, when the atlas has it.
+  Rune rune
+
+  // Index is the index of the source rune in []rune(text).
+  Index int
+
+  X, Y  float32
+  Scale float32
+
+  // Level is the bidi embedding level; odd levels are right-to-left.
+  Level uint8
+
+  // Span is the index of the glyph's span in the spans passed to
+  // LayoutSpans, and 0 for Layout.
+  Span int
+}
+
+// Quad is the screen and texture rectangle of a glyph.
+// (X0, Y0) is the top left corner, at (U0, V0) in the UV convention of the
+// atlas; V0 is the top row of the glyph's image.
+type Quad struct {
+  X0, Y0, X1, Y1 float32
+  U0, V0, U1, V1 float32
+  Page           int
+}
+
+// Quad returns the rectangle covering the glyph's atlas image, or the part
+// of it its UVs span if they're inset.
+func (g Glyph) Quad() Quad {
+  item := g.Item
+  inset := item.UVInset * g.Scale
+  x0 := g.X + item.BearingX*g.Scale
+  y1 := g.Y + item.Descent*g.Scale
+  return Quad{
+    X0:   x0 + inset,
+    Y0:   y1 - float32(item.Height)*g.Scale + inset,
+    X1:   x0 + float32(item.Width)*g.Scale - inset,
+    Y1:   y1 - inset,
+    U0:   item.U0,
+    V0:   item.V0,
+    U1:   item.U1,
+    V1:   item.V1,
+    Page: item.ImageIndex,
+  }
+}
+
+// Line is a laid out line of text.
+type Line struct {
+  // Start and End delimit the runes of the line, including trailing
+  // whitespace but not the line's newline.
+  Start, End int
+
+  // GlyphStart and GlyphEnd delimit the line's glyphs in Result.Glyphs.
+  GlyphStart, GlyphEnd int
+
+  // X is the left edge of the line's content and Y its baseline.
+  X, Y float32
+
+  // Width is the advance width of the content, without trailing whitespace
+  // and including any space added by justification.
+  Width float32
+
+  // Ascent and Descent extend the line box above and below the baseline,
+  // as far as the largest font on the line.
+  Ascent, Descent float32
+
+  // Level is the bidi level of the paragraph the line belongs to.
+  Level uint8
+
+  // Hyphenated is set when the line ends at a soft hyphen or hyphenation
+  // point and a hyphen glyph was added.
+  Hyphenated bool
+}
+
+// Top returns the top of the line box.
+func (l Line) Top() float32 {
+  return l.Y - l.Ascent
+}
+
+// Bottom returns the bottom of the line box.
+func (l Line) Bottom() float32 {
+  return l.Y + l.Descent
+}
+
+// Options configures layout.
+type Options struct {
+  // Size is the font size to lay out at; glyphs are scaled by Size/Atlas.FontPt.
+  Size float32
+
+  // Width and Height bound the layout box. Lines wrap at Width, and lines
+  // that would extend below Height are handled as Overflow says. Zero
+  // means unbounded.
+  Width, Height float32
+
+  // LineSpacing multiplies the atlas' recommended line height. Zero means 1.
+  LineSpacing float32
+
+  // Direction is the paragraph direction; Auto detects it per paragraph.
+  Direction bidi.Direction
+
+  // Align and VAlign place lines horizontally and vertically in the box.
+  // Without a Width, lines are aligned within the widest line; without a
+  // Height, VAlign has no effect.
+  Align  Align
+  VAlign VAlign
+
+  // JustifyCharacters lets justified lines stretch between ideographic
+  // characters as well as at spaces, as is usual for CJK text.
+  JustifyCharacters bool
+
+  // TabStops are the positions of tab stops from the start edge of the box,
+  // in increasing order. Past the last stop, tabs advance in steps of
+  // TabWidth, which defaults to the width of 8 spaces.
+  TabStops []float32
+  TabWidth float32
+
+  // Overflow is what to do with text that doesn't fit in Height.
+  Overflow Overflow
+
+  // Ellipsis is the text marking elided text, "…" by default or "..." if the
+  // atlas has no "…".
+  Ellipsis string
+
+  // MinSize is the smallest size OverflowShrink shrinks text to; zero means
+  // a quarter of Size.
+  MinSize float32
+
+  // Subpixel gives glyphs the subpixel variant of their atlas item nearest
+  // their position, for atlases built with ratlas.Options.SubpixelVariants.
+  // It applies to glyphs drawn at the atlas size, in a box at a whole pixel.
+  Subpixel bool
+
+  // Hyphenate, if set, returns the offsets within a word (a run of letters)
+  // at which it may be broken with a hyphen, such as []int{3} for "hyphen".
+  // Soft hyphens (U+00AD) in the text are always hyphenation points.
+  Hyphenate func(word []rune) []int
+}
+
+// Result is laid out text.
+type Result struct {
+  Glyphs []Glyph
+  Lines  []Line
+
+  // Width and Height are the size of the laid out lines, whose top is at
+  // y = 0 unless moved down by VAlign.
+  Width, Height float32
+
+  // Size is the font size the text was laid out at, which is smaller than
+  // Options.Size when OverflowShrink shrank it.
+  Size float32
+
+  // End is the index of the first rune that was not laid out, which is the
+  // number of runes when all the text fit.
+  End int
+
+  // Overflow is set when text didn't fit in Options.Height at Size, and was
+  // clipped or, if Truncated is set, elided.
+  Overflow bool
+
+  // Truncated is set when an ellipsis replaced the runes [Elided[0],
+  // Elided[1]) on the last line. Glyph and line indices still refer to the
+  // text passed to Layout, with the ellipsis glyphs at Elided[0], and End
+  // counts the elided runes as laid out.
+  Truncated bool
+  Elided    [2]int
+
+  // Underlines are the underlines of spans whose Style.Underline is set,
+  // one or more per line.
+  Underlines []Underline
+
+  // runes is the laid out text and edges the placement of each rune, for
+  // caret positioning.
+  runes []rune
+  edges []edge
+}
+
+// Quads returns the quads of all glyphs.
+func (r *Result) Quads() []Quad {
+  quads := make([]Quad, len(r.Glyphs))
+  for i, g := range r.Glyphs {
+    quads[i] = g.Quad()
+  }
+  return quads
+}
+
+// layouter holds the state of a single Layout call.
+type layouter struct {
+  atlas  *ratlas.Atlas
+  opts   Options
+  runes  []rune
+  scale  float32
+  result *Result
+
+  // styles[i] is the index in spans of the span rune i belongs to; both are
+  // nil for Layout.
+  styles []int
+  spans  []Span
+
+  // prefix[i] is the advance of runes [0, i), kerning included.
+  prefix []float32
+
+  // breaks[i] is the line break opportunity before rune i, and hyphens[i]
+  // is set where breaking before rune i adds a hyphen.
+  breaks  []linebreak.Break
+  hyphens []bool
+
+  // hyphen is the atlas item drawn at hyphenated line ends, unless the
+  // line's last rune is in a span whose font has its own.
+  hyphenRune rune
+  hyphen     *ratlas.AtlasItem
+
+  // lines holds justification details of each line in result.Lines, and
+  // edgeGaps the justification opportunities before the left and right
+  // edges of each rune.
+  lines    []lineInfo
+  edgeGaps [][2]int
+
+  // split is set when a line had to be broken inside a word.
+  split bool
+}
+
+const softHyphen = 0x00AD
+
+// invisible reports whether r takes no advance of its own and is never drawn.
+// Tabs are advanced to the next tab stop separately.
+func invisible(r rune) bool {
+  switch r {
+  case '\t', softHyphen, 0x200B, 0x200C, 0x200D, 0x2060, 0xFEFF:
+    return true
+  }
+  return false
+}
+
+func (l *layouter) item(i int) (*ratlas.AtlasItem, bool) {
+  if invisible(l.runes[i]) {
+    return nil, false
+  }
+  atlas, _ := l.font(i)
+  item, ok := atlas.Items[l.runes[i]]
+  return item, ok
+}
+
+// kern returns the scaled kerning between runes i and i+1.
+func (l *layouter) kern(i int) float32 {
+  if i < 0 || i+1 >= len(l.runes) {
+    return 0
+  }
+  _, ok0 := l.item(i)
+  _, ok1 := l.item(i + 1)
+  if !ok0 || !ok1 {
+    return 0
+  }
+  atlas, scale := l.font(i)
+  if next, nextScale := l.font(i + 1); next != atlas || nextScale != scale {
+    return 0
+  }
+  return atlas.Kern(l.runes[i], l.runes[i+1]) * scale
+}
+
+// width returns the advance width of runes [start, end) in logical order.
+func (l *layouter) width(start, end int) float32 {
+  if end <= start {
+    return 0
+  }
+  return l.prefix[end] - l.prefix[start] - l.kern(end-1)
+}
+
+// measure returns the advance width of runes [start, end) in logical order,
+// advancing tabs to tab stops measured from start.
+func (l *layouter) measure(start, end int) float32 {
+  var pen float32
+  for i := start; i < end; i++ {
+    if l.runes[i] == '\t' {
+      pen = l.nextTab(pen + l.width(start, i))
+      start = i + 1
+    }
+  }
+  return pen + l.width(start, end)
+}
+
+// isSpace reports whether r is whitespace that hangs past the end of a line.
+func isSpace(r rune) bool {
+  switch r {
+  case ' ', '\t', '\r', 0x0B, 0x0C, 0x85, 0x200B, 0x2028, 0x2029:
+    return true
+  }
+  return false
+}
+
+// trimEnd returns the end of [start, end) without trailing whitespace.
+func (l *layouter) trimEnd(start, end int) int {
+  for end > start && isSpace(l.runes[end-1]) {
+    end--
+  }
+  return end
+}
+
+// Layout lays out text in the box described by opts.
+func Layout(atlas *ratlas.Atlas, text string, opts Options) *Result {
+  return layout(atlas, []rune(text), nil, nil, opts)
+}
+
+func layout(atlas *ratlas.Atlas, runes []rune, styles []int, spans []Span, opts Options) *Result {
+  if opts.LineSpacing == 0 {
+    opts.LineSpacing = 1
+  }
+  switch opts.Overflow {
+  case OverflowShrink:
+    return shrink(atlas, runes, styles, spans, opts)
+  case OverflowEllipsisEnd, OverflowEllipsisMiddle, OverflowEllipsisStart:
+    l := newLayouter(atlas, runes, styles, spans, opts)
+    l.run()
+    if l.result.Overflow {
+      return l.ellipsize()
+    }
+    return l.result
+  }
+  l := newLayouter(atlas, runes, styles, spans, opts)
+  l.run()
+  return l.result
+}
+
+func newLayouter(atlas *ratlas.Atlas, runes []rune, styles []int, spans []Span, opts Options) *layouter {
+  l := &layouter{
+    atlas:  atlas,
+    opts:   opts,
+    runes:  runes,
+    scale:  opts.Size / float32(atlas.FontPt),
+    result: &Result{Size: opts.Size},
+    styles: styles,
+    spans:  spans,
+  }
+  l.result.runes = l.runes
+  l.result.edges = make([]edge, len(l.runes))
+  l.edgeGaps = make([][2]int, len(l.runes))
+  if l.opts.TabWidth <= 0 {
+    l.opts.TabWidth = 8 * opts.Size / 4
+    if space, ok := atlas.Items[' ']; ok {
+      l.opts.TabWidth = 8 * space.Advance * l.scale
+    }
+  }
+  for _, r := range []rune{'-', 0x2010} {
+    if item, ok := atlas.Items[r]; ok {
+      l.hyphenRune, l.hyphen = r, item
+      break
+    }
+  }
+
+  l.breaks = make([]linebreak.Break, len(l.runes)+1)
+  l.hyphens = make([]bool, len(l.runes)+1)
+  l.prefix = make([]float32, len(l.runes)+1)
+  for i := range l.runes {
+    var advance float32
+    if item, ok := l.item(i); ok {
+      _, scale := l.font(i)
+      advance = item.Advance * scale
+    }
+    l.prefix[i+1] = l.prefix[i] + advance + l.kern(i)
+  }
+  return l
+}
+
+// run lays out the text, stopping at the first line that doesn't fit.
+func (l *layouter) run() {
+  var y, prevDescent, prevGap float32
+
+  start := 0
+  for start <= len(l.runes) {
+    // split paragraphs at newlines
+    end := start
+    for end < len(l.runes) && l.runes[end] != '\n' {
+      end++
+    }
+    paraEnd := end
+    if paraEnd > start && l.runes[paraEnd-1] == '\r' {
+      paraEnd--
+    }
+
+    paragraph := bidi.NewParagraph(l.runes[start:paraEnd], l.opts.Direction)
+    l.findBreaks(start, paraEnd)
+    for _, line := range l.wrap(start, paraEnd) {
+      // baselines are spaced by the line height of the fonts on either side
+      ascent, descent, gap := l.metrics(line[0], line[1])
+      if len(l.result.Lines) == 0 {
+        y = ascent
+      } else {
+        y += (prevDescent + prevGap + ascent) * l.opts.LineSpacing
+      }
+      prevDescent, prevGap = descent, gap
+      if l.opts.Height > 0 && y+descent > l.opts.Height {
+        l.result.End = line[0]
+        l.result.Overflow = true
+        l.finish()
+        return
+      }
+      l.addLine(paragraph, start, line[0], line[1], y, ascent, descent)
+    }
+
+    start = end + 1
+  }
+  l.result.End = len(l.runes)
+  l.finish()
+}
+
+// finish positions the glyphs of laid out lines.
+func (l *layouter) finish() {
+  l.align()
+  if l.opts.Subpixel {
+    for i := range l.result.Glyphs {
+      g := &l.result.Glyphs[i]
+      if g.Item != nil && g.Scale == 1 {
+        g.Item = g.Item.Variant(g.X)
+      }
+    }
+  }
+  l.underline()
+}
+
+// findBreaks finds the line break opportunities of the paragraph [start, end).
+func (l *layouter) findBreaks(start, end int) {
+  copy(l.breaks[start:end+1], linebreak.Breaks(l.runes[start:end]))
+  for i := start + 1; i < end; i++ {
+    if l.runes[i-1] == softHyphen && l.breaks[i] == linebreak.Allowed {
+      l.hyphens[i] = l.hyphenAt(i-1).item != nil
+    }
+  }
+  if l.opts.Hyphenate == nil || l.hyphen == nil {
+    return
+  }
+  for i := start; i < end; {
+    if !unicode.IsLetter(l.runes[i]) {
+      i++
+      continue
+    }
+    j := i
+    for j < end && (unicode.IsLetter(l.runes[j]) || unicode.In(l.runes[j], unicode.Mn, unicode.Mc)) {
+      j++
+    }
+    for _, offset := range l.opts.Hyphenate(l.runes[i:j]) {
+      if k := i + offset; k > i && k < j && l.breaks[k] == linebreak.Prohibited {
+        l.breaks[k] = linebreak.Allowed
+        l.hyphens[k] = true
+      }
+    }
+    i = j
+  }
+}
+
+// lineWidth returns the width of a line holding runes [start, end), without
+// trailing whitespace and with a hyphen if the line breaks at one.
+func (l *layouter) lineWidth(start, end int) float32 {
+  w := l.measure(start, l.trimEnd(start, end))
+  if l.hyphens[end] {
+    hyphen := l.hyphenAt(end - 1)
+    w += hyphen.item.Advance * hyphen.scale
+  }
+  return w
+}
+
+// wrap breaks the paragraph [start, end) into lines, returned as rune ranges.
+func (l *layouter) wrap(start, end int) [][2]int {
+  if start == end {
+    return [][2]int{{start, end}}
+  }
+
+  var lines [][2]int
+  lineStart := start
+  for lineStart < end {
+    lineEnd := lineStart
+    for i := lineStart + 1; i <= end; i++ {
+      if l.breaks[i] == linebreak.Prohibited {
+        continue
+      }
+      if l.opts.Width > 0 && l.lineWidth(lineStart, i) > l.opts.Width {
+        break
+      }
+      lineEnd = i
+      if l.breaks[i] == linebreak.Mandatory {
+        break
+      }
+    }
+    if lineEnd == lineStart {
+      // nothing fits; break between runes, keeping combining marks with
+      // their base and stopping at mandatory breaks
+      l.split = true
+      lineEnd = lineStart + 1
+      for lineEnd < end && l.breaks[lineEnd] != linebreak.Mandatory {
+        r := l.runes[lineEnd]
+        if !unicode.In(r, unicode.Mn, unicode.Me) && r != 0x200D && l.measure(lineStart, lineEnd+1) > l.opts.Width {
+          break
+        }
+        lineEnd++
+      }
+    }
+    lines = append(lines, [2]int{lineStart, lineEnd})
+    lineStart = lineEnd
+  }
+  return lines
+}
+
+// placed is a rune of a line being placed, in visual order. item is nil
+// for tabs and runes that aren't drawn.
+type placed struct {
+  index    int
+  r        rune
+  item     *ratlas.AtlasItem
+  atlas    *ratlas.Atlas
+  scale    float32
+  span     int
+  level    uint8
+  tab      bool
+  trailing bool
+  hyphen   bool
+}
+
+// addLine positions the runes [start, end) of a paragraph beginning at
+// paraStart as a line with baseline y. Glyphs are placed with the line's
+// content starting at x = 0; align moves them into place.
+func (l *layouter) addLine(paragraph *bidi.Paragraph, paraStart, start, end int, y, ascent, descent float32) {
+  contentEnd := l.trimEnd(start, end)
+  line := Line{
+    Hyphenated: l.hyphens[end] && contentEnd == end,
+    Start:      start,
+    End:        end,
+    GlyphStart: len(l.result.Glyphs),
+    Y:          y,
+    Ascent:     ascent,
+    Descent:    descent,
+    Level:      paragraph.Level(),
+  }
+
+  levels := paragraph.LineLevels(start-paraStart, end-paraStart)
+  var items []placed
+  for _, pi := range paragraph.Reorder(start-paraStart, end-paraStart) {
+    i := paraStart + pi
+    r := l.runes[i]
+    level := levels[pi-(start-paraStart)]
+    trailing := i >= contentEnd
+    atlas, scale := l.font(i)
+    span := 0
+    if l.styles != nil {
+      span = l.styles[i]
+    }
+
+    // the hyphen of a hyphenated line follows its last rune logically,
+    // replacing a soft hyphen
+    hyphen := l.hyphenAt(i)
+    hyphen.index, hyphen.span, hyphen.level, hyphen.hyphen = i, span, level, true
+    addHyphen := line.Hyphenated && i == end-1
+    if addHyphen && level&1 == 1 {
+      items = append(items, hyphen)
+    }
+    // mirror only if the atlas has the mirrored rune, drawing the source
+    // rune rather than nothing
+    if level&1 == 1 {
+      if m, ok := bidi.Mirror(r); ok && atlas.Items[m] != nil {
+        r = m
+      }
+    }
+    item, ok := atlas.Items[r]
+    if !ok || invisible(r) {
+      item = nil
+    }
+    items = append(items, placed{index: i, r: r, item: item, atlas: atlas, scale: scale, span: span, level: level, tab: r == '\t', trailing: trailing})
+    if addHyphen && level&1 == 0 {
+      items = append(items, hyphen)
+    }
+  }
+
+  // walk from the line's start edge, which is the right edge in
+  // right-to-left paragraphs, so tabs advance away from it. Trailing
+  // whitespace comes last either way.
+  rtl := line.Level&1 == 1
+  if rtl {
+    for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+      items[i], items[j] = items[j], items[i]
+    }
+  }
+  info := lineInfo{last: l.breaks[end] == linebreak.Mandatory}
+  var pen float32
+  var prev *placed
+  var gaps []int
+  for k := range items {
+    it := &items[k]
+    from, fromGaps := pen, info.gaps
+    switch {
+    case it.tab:
+      pen = l.nextTab(pen)
+      prev = nil
+      info.tabbed = true
+    case it.item == nil:
+      prev = nil
+    default:
+      if prev != nil {
+        left, right := prev.r, it.r
+        if rtl {
+          left, right = right, left
+        }
+        if prev.atlas == it.atlas && prev.scale == it.scale {
+          pen += it.atlas.Kern(left, right) * it.scale
+        }
+        if l.opts.JustifyCharacters && !it.trailing && ideographic(prev.r, it.r) {
+          info.gaps++
+        }
+      }
+      from, fromGaps = pen, info.gaps
+      advance := it.item.Advance * it.scale
+      x := pen
+      if rtl {
+        x = -pen - advance
+      }
+      l.result.Glyphs = append(l.result.Glyphs, Glyph{
+        Item:  it.item,
+        Rune:  it.r,
+        Index: it.index,
+        X:     x,
+        Y:     y,
+        Scale: it.scale,
+        Level: it.level,
+        Span:  it.span,
+      })
+      gaps = append(gaps, info.gaps)
+      pen += advance
+      if !it.trailing {
+        line.Width = pen
+        if it.r == ' ' {
+          info.gaps++
+        }
+      }
+      prev = it
+    }
+    if it.hyphen {
+      continue
+    }
+    e := edge{level: it.level, left: from, right: pen}
+    eg := [2]int{fromGaps, info.gaps}
+    if rtl {
+      e.left, e.right = -pen, -from
+      eg[0], eg[1] = eg[1], eg[0]
+    }
+    l.result.edges[it.index] = e
+    l.edgeGaps[it.index] = eg
+  }
+  line.GlyphEnd = len(l.result.Glyphs)
+
+  // right-to-left content ends at x = Width
+  if rtl {
+    for k := line.GlyphStart; k < line.GlyphEnd; k++ {
+      l.result.Glyphs[k].X += line.Width
+    }
+    for i := start; i < end; i++ {
+      l.result.edges[i].left += line.Width
+      l.result.edges[i].right += line.Width
+    }
+  }
+
+  info.glyphGaps = gaps
+  l.result.Lines = append(l.result.Lines, line)
+  l.lines = append(l.lines, info)
+}
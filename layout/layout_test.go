@@ -0,0 +1,106 @@
+package layout
+
+import (
+  "io/ioutil"
+  "math"
+  "strings"
+  "sync"
+  "testing"
+
+  "github.com/vrav/ratlas"
+)
+
+var (
+  testAtlasOnce sync.Once
+  testAtlasValue ratlas.Atlas
+)
+
+// testAtlas returns an atlas of printable ASCII and an ellipsis, built once
+// from the example font.
+func testAtlas(t *testing.T) *ratlas.Atlas {
+  testAtlasOnce.Do(func() {
+    data, err := ioutil.ReadFile("../example/Vera.ttf")
+    if err != nil {
+      t.Fatal(err)
+    }
+    var runes []rune
+    for r := rune(' '); r <= '~'; r++ {
+      runes = append(runes, r)
+    }
+    runes = append(runes, '…')
+    testAtlasValue = ratlas.NewWithOptions(&data, runes, ratlas.Options{FontPt: 32, Width: 512, Height: 512, Pad: 2})
+  })
+  if testAtlasValue.Items == nil {
+    t.Fatal("no test atlas")
+  }
+  return &testAtlasValue
+}
+
+func near(a, b float32) bool {
+  return math.Abs(float64(a-b)) < 0.01
+}
+
+const testText = "The quick brown fox jumps over the lazy dog, then naps in the sun for a while."
+
+// lineText returns the runes of a line, trailing whitespace included.
+func lineText(text string, line Line) string {
+  return string([]rune(text)[line.Start:line.End])
+}
+
+func TestWrap(t *testing.T) {
+  atlas := testAtlas(t)
+  for _, test := range []struct {
+    text  string
+    width float32
+    lines []string
+  }{
+    {"hello world", 0, []string{"hello world"}},
+    {"hello\nworld", 0, []string{"hello", "world"}},
+    {"hello world", 60, []string{"hello ", "world"}},
+    {"a b c", 1000, []string{"a b c"}},
+    {"", 100, []string{""}},
+  } {
+    r := Layout(atlas, test.text, Options{Size: 16, Width: test.width})
+    var got []string
+    for _, line := range r.Lines {
+      got = append(got, lineText(test.text, line))
+    }
+    if strings.Join(got, "|") != strings.Join(test.lines, "|") {
+      t.Errorf("%q at width %v: lines %q, want %q", test.text, test.width, got, test.lines)
+    }
+  }
+
+  for _, width := range []float32{80, 150, 300} {
+    r := Layout(atlas, testText, Options{Size: 16, Width: width})
+    if len(r.Lines) < 2 {
+      t.Errorf("width %v: %d lines", width, len(r.Lines))
+    }
+    next := 0
+    for i, line := range r.Lines {
+      if line.Start != next {
+        t.Errorf("width %v: line %d starts at %d, not %d", width, i, line.Start, next)
+      }
+      next = line.End
+      if line.Width > width+0.01 {
+        t.Errorf("width %v: line %d %q is %v wide", width, i, lineText(testText, line), line.Width)
+      }
+      if i > 0 && line.Y <= r.Lines[i-1].Y {
+        t.Errorf("width %v: line %d at y %v, above line %d at %v", width, i, line.Y, i-1, r.Lines[i-1].Y)
+      }
+    }
+    if next != len([]rune(testText)) || r.End != next {
+      t.Errorf("width %v: lines end at %d, End is %d, want %d", width, next, r.End, len([]rune(testText)))
+    }
+  }
+}
+
+// glyphAt returns the glyph of rune index i.
+func glyphAt(t *testing.T, r *Result, i int) Glyph {
+  for _, g := range r.Glyphs {
+    if g.Index == i {
+      return g
+    }
+  }
+  t.Fatalf("no glyph for rune %d", i)
+  return Glyph{}
+}
@@ -0,0 +1,195 @@
+package layout
+
+import (
+  "fmt"
+  "image/color"
+  "strconv"
+  "strings"
+
+  "github.com/vrav/ratlas"
+)
+
+// ParseMarkup parses BBCode-like markup into spans for LayoutSpans. Text is
+// drawn in base, changed by nested tags:
+//
+//  [b]bold[/b] [i]italic[/i] [u]underlined[/u]
+//  [color=#ff8000]orange[/color] [color=red]red[/color]
+//  [size=1.5]one and a half times Options.Size[/size]
+//  [font=icons]glyphs of fonts["icons"][/font]
+//
+// [b] and [i] switch to fonts["bold"], fonts["italic"] or
+// fonts["bold-italic"], prefixed by the family set by [font=name] as in
+// "name-bold", falling back to the closest font present. "[[" is a literal
+// "[". Tags must be closed in order.
+func ParseMarkup(markup string, base Style, fonts map[string]*ratlas.Atlas) ([]Span, error) {
+  type state struct {
+    tag          string
+    style        Style
+    family       string
+    bold, italic bool
+  }
+  stack := []state{{style: base}}
+
+  var spans []Span
+  var text strings.Builder
+  flush := func() {
+    if text.Len() == 0 {
+      return
+    }
+    style := stack[len(stack)-1].style
+    if n := len(spans); n > 0 && sameStyle(spans[n-1].Style, style) {
+      spans[n-1].Text += text.String()
+    } else {
+      spans = append(spans, Span{Text: text.String(), Style: style})
+    }
+    text.Reset()
+  }
+
+  for i := 0; i < len(markup); {
+    if markup[i] != '[' {
+      text.WriteByte(markup[i])
+      i++
+      continue
+    }
+    if strings.HasPrefix(markup[i:], "[[") {
+      text.WriteByte('[')
+      i += 2
+      continue
+    }
+    end := strings.IndexByte(markup[i:], ']')
+    if end < 0 {
+      return nil, fmt.Errorf("layout: unterminated tag at byte %d", i)
+    }
+    tag := markup[i+1 : i+end]
+    i += end + 1
+    flush()
+
+    if strings.HasPrefix(tag, "/") {
+      if len(stack) == 1 || stack[len(stack)-1].tag != tag[1:] {
+        return nil, fmt.Errorf("layout: unexpected [%s]", tag)
+      }
+      stack = stack[:len(stack)-1]
+      continue
+    }
+
+    name, value := tag, ""
+    if k := strings.IndexByte(tag, '='); k >= 0 {
+      name, value = tag[:k], tag[k+1:]
+    }
+    s := stack[len(stack)-1]
+    s.tag = name
+    switch name {
+    case "b":
+      s.bold = true
+      s.style.Atlas = markupFont(fonts, s.family, s.bold, s.italic, s.style.Atlas)
+    case "i":
+      s.italic = true
+      s.style.Atlas = markupFont(fonts, s.family, s.bold, s.italic, s.style.Atlas)
+    case "u":
+      s.style.Underline = true
+    case "color":
+      c, err := parseColor(value)
+      if err != nil {
+        return nil, err
+      }
+      s.style.Color = c
+    case "size":
+      scale, err := strconv.ParseFloat(value, 32)
+      if err != nil || scale <= 0 {
+        return nil, fmt.Errorf("layout: bad size %q", value)
+      }
+      s.style.Scale = float32(scale)
+    case "font":
+      if _, ok := fonts[value]; !ok {
+        return nil, fmt.Errorf("layout: unknown font %q", value)
+      }
+      s.family = value
+      s.style.Atlas = markupFont(fonts, s.family, s.bold, s.italic, fonts[value])
+    default:
+      return nil, fmt.Errorf("layout: unknown tag [%s]", tag)
+    }
+    stack = append(stack, s)
+  }
+  flush()
+
+  if len(stack) > 1 {
+    return nil, fmt.Errorf("layout: unclosed tag [%s]", stack[len(stack)-1].tag)
+  }
+  return spans, nil
+}
+
+// markupFont returns the font of a family in bold and/or italic, or with
+// fewer of them when fonts lacks it, or fallback.
+func markupFont(fonts map[string]*ratlas.Atlas, family string, bold, italic bool, fallback *ratlas.Atlas) *ratlas.Atlas {
+  join := func(parts ...string) string {
+    var names []string
+    for _, part := range parts {
+      if part != "" {
+        names = append(names, part)
+      }
+    }
+    return strings.Join(names, "-")
+  }
+  var names []string
+  switch {
+  case bold && italic:
+    names = []string{join(family, "bold", "italic"), join(family, "bold"), join(family, "italic")}
+  case bold:
+    names = []string{join(family, "bold")}
+  case italic:
+    names = []string{join(family, "italic")}
+  }
+  for _, name := range append(names, family) {
+    if atlas, ok := fonts[name]; ok && name != "" {
+      return atlas
+    }
+  }
+  return fallback
+}
+
+// sameStyle reports whether a and b draw alike, comparing colors by value as
+// color.Color implementations needn't be comparable.
+func sameStyle(a, b Style) bool {
+  if a.Atlas != b.Atlas || a.Scale != b.Scale || a.Underline != b.Underline {
+    return false
+  }
+  if a.Color == nil || b.Color == nil {
+    return a.Color == nil && b.Color == nil
+  }
+  ar, ag, ab, aa := a.Color.RGBA()
+  br, bg, bb, ba := b.Color.RGBA()
+  return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+var colorNames = map[string]color.NRGBA{
+  "black":   {0, 0, 0, 255},
+  "white":   {255, 255, 255, 255},
+  "gray":    {128, 128, 128, 255},
+  "red":     {255, 0, 0, 255},
+  "green":   {0, 255, 0, 255},
+  "blue":    {0, 0, 255, 255},
+  "yellow":  {255, 255, 0, 255},
+  "cyan":    {0, 255, 255, 255},
+  "magenta": {255, 0, 255, 255},
+  "orange":  {255, 165, 0, 255},
+}
+
+// parseColor parses a color name or a "#rgb", "#rrggbb" or "#rrggbbaa" hex
+// color.
+func parseColor(s string) (color.NRGBA, error) {
+  if c, ok := colorNames[strings.ToLower(s)]; ok {
+    return c, nil
+  }
+  hex := strings.TrimPrefix(s, "#")
+  if len(hex) == 3 {
+    hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+  }
+  if len(hex) == 6 {
+    hex += "ff"
+  }
+  v, err := strconv.ParseUint(hex, 16, 32)
+  if err != nil || len(hex) != 8 || !strings.HasPrefix(s, "#") {
+    return color.NRGBA{}, fmt.Errorf("layout: bad color %q", s)
+  }
+  return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
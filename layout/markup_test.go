@@ -0,0 +1,245 @@
+package layout
+
+import (
+  "image/color"
+  "strings"
+  "testing"
+
+  "github.com/vrav/ratlas"
+)
+
+// paletteColor is a color.Color that can't be compared with ==.
+type paletteColor struct {
+  rgba []uint8
+}
+
+func (c paletteColor) RGBA() (r, g, b, a uint32) {
+  return color.NRGBA{c.rgba[0], c.rgba[1], c.rgba[2], c.rgba[3]}.RGBA()
+}
+
+// markupFonts returns fonts for ParseMarkup, told apart by name, and the
+// regular font.
+func markupFonts() (map[string]*ratlas.Atlas, *ratlas.Atlas) {
+  fonts := make(map[string]*ratlas.Atlas)
+  for _, name := range []string{"bold", "italic", "bold-italic", "mono", "mono-bold"} {
+    fonts[name] = &ratlas.Atlas{}
+  }
+  return fonts, &ratlas.Atlas{}
+}
+
+func TestParseMarkup(t *testing.T) {
+  fonts, regular := markupFonts()
+  fontName := func(atlas *ratlas.Atlas) string {
+    for name, f := range fonts {
+      if f == atlas {
+        return name
+      }
+    }
+    if atlas == regular {
+      return "regular"
+    }
+    return "?"
+  }
+  type span struct {
+    text      string
+    font      string
+    scale     float32
+    underline bool
+    color     color.Color
+  }
+  orange := color.NRGBA{255, 128, 0, 255}
+  red := color.NRGBA{255, 0, 0, 255}
+  for _, test := range []struct {
+    markup string
+    spans  []span
+  }{
+    {"plain", []span{{"plain", "regular", 0, false, nil}}},
+    {"a[b]b[i]bi[/i][/b][i]i[/i]", []span{
+      {"a", "regular", 0, false, nil},
+      {"b", "bold", 0, false, nil},
+      {"bi", "bold-italic", 0, false, nil},
+      {"i", "italic", 0, false, nil},
+    }},
+    // no mono-italic or mono-bold-italic: the closest font is used, and
+    // alike spans merge
+    {"[font=mono]m[i]mi[/i][b]mb[i]mbi[/i][/b][/font]", []span{
+      {"mmi", "mono", 0, false, nil},
+      {"mbmbi", "mono-bold", 0, false, nil},
+    }},
+    {"[[b]] [[[u]x[/u]", []span{
+      {"[b]] [", "regular", 0, false, nil},
+      {"x", "regular", 0, true, nil},
+    }},
+    {"[u]u[/u][size=1.5]s[/size][color=#ff8000]o[/color][color=red]r[/color]", []span{
+      {"u", "regular", 0, true, nil},
+      {"s", "regular", 1.5, false, nil},
+      {"o", "regular", 0, false, orange},
+      {"r", "regular", 0, false, red},
+    }},
+    {"[color=#f00]a[/color][color=RED]b[/color][color=#ff0000ff]c[/color]", []span{
+      {"abc", "regular", 0, false, red},
+    }},
+    {"[size=2][u][color=#ff8000][b]x[/b][/color][/u][/size]", []span{
+      {"x", "bold", 2, true, orange},
+    }},
+    {"", nil},
+  } {
+    spans, err := ParseMarkup(test.markup, Style{Atlas: regular}, fonts)
+    if err != nil {
+      t.Errorf("%q: %v", test.markup, err)
+      continue
+    }
+    var got []span
+    for _, s := range spans {
+      got = append(got, span{s.Text, fontName(s.Style.Atlas), s.Style.Scale, s.Style.Underline, s.Style.Color})
+    }
+    if len(got) != len(test.spans) {
+      t.Errorf("%q: spans %v, want %v", test.markup, got, test.spans)
+      continue
+    }
+    for i := range got {
+      if got[i] != test.spans[i] {
+        t.Errorf("%q: spans %v, want %v", test.markup, got, test.spans)
+        break
+      }
+    }
+  }
+}
+
+func TestParseMarkupErrors(t *testing.T) {
+  fonts, regular := markupFonts()
+  for _, test := range []struct {
+    markup, err string
+  }{
+    {"[b]bold", "unclosed tag [b]"},
+    {"[b][i]x[/b][/i]", "unexpected [/b]"},
+    {"x[/u]", "unexpected [/u]"},
+    {"[b", "unterminated tag at byte 0"},
+    {"[size=0]x[/size]", `bad size "0"`},
+    {"[size=big]x[/size]", `bad size "big"`},
+    {"[color=#12]x[/color]", `bad color "#12"`},
+    {"[color=ff0000]x[/color]", `bad color "ff0000"`},
+    {"[color=mauve]x[/color]", `bad color "mauve"`},
+    {"[font=serif]x[/font]", `unknown font "serif"`},
+    {"[blink]x[/blink]", "unknown tag [blink]"},
+  } {
+    spans, err := ParseMarkup(test.markup, Style{Atlas: regular}, fonts)
+    if err == nil || !strings.Contains(err.Error(), test.err) {
+      t.Errorf("%q: spans %v, error %v, want %q", test.markup, spans, err, test.err)
+    }
+  }
+}
+
+func TestMarkupFont(t *testing.T) {
+  fonts, regular := markupFonts()
+  for _, test := range []struct {
+    family       string
+    bold, italic bool
+    want         *ratlas.Atlas
+  }{
+    {"", false, false, regular},
+    {"", true, false, fonts["bold"]},
+    {"", true, true, fonts["bold-italic"]},
+    {"mono", false, false, fonts["mono"]},
+    {"mono", false, true, fonts["mono"]},
+    {"mono", true, true, fonts["mono-bold"]},
+    {"serif", true, false, regular},
+  } {
+    if got := markupFont(fonts, test.family, test.bold, test.italic, regular); got != test.want {
+      t.Errorf("family %q, bold %v, italic %v: wrong font", test.family, test.bold, test.italic)
+    }
+  }
+  delete(fonts, "bold-italic")
+  if markupFont(fonts, "", true, true, regular) != fonts["bold"] {
+    t.Errorf("bold italic doesn't fall back to bold")
+  }
+  if markupFont(nil, "", true, false, regular) != regular {
+    t.Errorf("no fonts doesn't fall back")
+  }
+}
+
+func TestSameStyle(t *testing.T) {
+  a, b := &ratlas.Atlas{}, &ratlas.Atlas{}
+  gray := paletteColor{[]uint8{128, 128, 128, 255}}
+  for _, test := range []struct {
+    a, b Style
+    same bool
+  }{
+    {Style{}, Style{}, true},
+    {Style{Atlas: a}, Style{Atlas: b}, false},
+    {Style{Scale: 2}, Style{Scale: 2}, true},
+    {Style{Scale: 2}, Style{}, false},
+    {Style{Underline: true}, Style{}, false},
+    {Style{Color: color.White}, Style{}, false},
+    {Style{Color: color.White}, Style{Color: color.NRGBA{255, 255, 255, 255}}, true},
+    {Style{Color: color.White}, Style{Color: color.Black}, false},
+    // colors that panic when compared with ==
+    {Style{Color: gray}, Style{Color: paletteColor{[]uint8{128, 128, 128, 255}}}, true},
+    {Style{Color: gray}, Style{Color: paletteColor{[]uint8{128, 128, 128, 0}}}, false},
+    {Style{Color: gray}, Style{Color: color.NRGBA{128, 128, 128, 255}}, true},
+  } {
+    if got := sameStyle(test.a, test.b); got != test.same || sameStyle(test.b, test.a) != got {
+      t.Errorf("sameStyle(%+v, %+v) is %v, want %v", test.a, test.b, got, test.same)
+    }
+  }
+
+  // spans in such a color still merge
+  spans, err := ParseMarkup("a[b]b[/b]c", Style{Color: gray}, nil)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(spans) != 1 || spans[0].Text != "abc" {
+    t.Errorf("spans %+v, want one", spans)
+  }
+}
+
+func TestLayoutSpansUnderline(t *testing.T) {
+  atlas := testAtlas(t)
+  const size = 16
+  width := func(text string) float32 {
+    return Layout(atlas, text, Options{Size: size}).Lines[0].Width
+  }
+  spans, err := ParseMarkup("plain [u]underlined text[/u] and more", Style{}, nil)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  r := LayoutSpans(atlas, spans, Options{Size: size})
+  if len(r.Underlines) != 1 {
+    t.Fatalf("%d underlines, want 1", len(r.Underlines))
+  }
+  u, line := r.Underlines[0], r.Lines[0]
+  if u.Span != 1 {
+    t.Errorf("underline of span %d, want 1", u.Span)
+  }
+  x0 := glyphAt(t, r, len("plain ")).X
+  if x1 := width("plain underlined text"); !near(u.X0, x0) || !near(u.X1, x1) {
+    t.Errorf("underline from %v to %v, want %v to %v", u.X0, u.X1, x0, x1)
+  }
+  if u.Y0 <= line.Y || u.Y1 < u.Y0+1 || u.Y1 > line.Y+line.Descent {
+    t.Errorf("underline from y %v to %v, below a baseline at %v with descent %v", u.Y0, u.Y1, line.Y, line.Descent)
+  }
+
+  // wrapped, the span is underlined on each of its lines, without the
+  // space at the end of the first
+  r = LayoutSpans(atlas, spans, Options{Size: size, Width: width("plain underlined")})
+  if len(r.Underlines) != 2 {
+    t.Fatalf("%d underlines when wrapped, want 2", len(r.Underlines))
+  }
+  for i, u := range r.Underlines {
+    line := r.Lines[i]
+    if u.Span != 1 || u.Y0 <= line.Y || u.Y0 >= line.Y+line.Descent {
+      t.Errorf("underline %d of span %d at y %v, on line %d at %v", i, u.Span, u.Y0, i, line.Y)
+    }
+  }
+  if x1 := width("plain underlined"); !near(r.Underlines[0].X0, x0) || !near(r.Underlines[0].X1, x1) {
+    t.Errorf("first underline from %v to %v, want %v to %v", r.Underlines[0].X0, r.Underlines[0].X1, x0, x1)
+  }
+  if x1 := width("text"); !near(r.Underlines[1].X0, 0) || !near(r.Underlines[1].X1, x1) {
+    t.Errorf("second underline from %v to %v, want 0 to %v", r.Underlines[1].X0, r.Underlines[1].X1, x1)
+  }
+
+  if r := Layout(atlas, "plain underlined text", Options{Size: size}); len(r.Underlines) != 0 {
+    t.Errorf("%d underlines without spans", len(r.Underlines))
+  }
+}
@@ -0,0 +1,237 @@
+package layout
+
+import (
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/grapheme"
+)
+
+// Overflow is what Layout does with text that doesn't fit in Options.Height.
+type Overflow int
+
+const (
+  // OverflowClip drops the lines that don't fit.
+  OverflowClip Overflow = iota
+
+  // OverflowEllipsisEnd, OverflowEllipsisMiddle and OverflowEllipsisStart
+  // clip like OverflowClip, and then replace text on the last visible line
+  // with Options.Ellipsis: the end of the line and everything after it, the
+  // middle of the line and everything up to the end of the text, or the
+  // line up to the end of the text.
+  OverflowEllipsisEnd
+  OverflowEllipsisMiddle
+  OverflowEllipsisStart
+
+  // OverflowShrink reduces the font size, down to Options.MinSize, until the
+  // text fits without breaking words, and clips at MinSize.
+  OverflowShrink
+)
+
+// shrink lays out runes at the largest size that fits, searching between
+// opts.MinSize and opts.Size.
+func shrink(atlas *ratlas.Atlas, runes []rune, styles []int, spans []Span, opts Options) *Result {
+  try := func(size float32) (*Result, bool) {
+    o := opts
+    o.Size = size
+    l := newLayouter(atlas, runes, styles, spans, o)
+    l.run()
+    return l.result, !l.result.Overflow && !l.split
+  }
+
+  best, fits := try(opts.Size)
+  if fits {
+    return best
+  }
+  lo, hi := opts.MinSize, opts.Size
+  if lo <= 0 {
+    lo = opts.Size / 4
+  }
+  if lo >= hi {
+    return best
+  }
+  best, fits = try(lo)
+  if !fits {
+    return best
+  }
+  for i := 0; i < 8; i++ {
+    mid := (lo + hi) / 2
+    if result, fits := try(mid); fits {
+      best, lo = result, mid
+    } else {
+      hi = mid
+    }
+  }
+  return best
+}
+
+// mandatoryBreak reports whether r ends a line.
+func mandatoryBreak(r rune) bool {
+  switch r {
+  case '\n', '\r', 0x0B, 0x0C, 0x85, 0x2028, 0x2029:
+    return true
+  }
+  return false
+}
+
+// ellipsize replaces text on the last line of an overflowing layout with
+// the ellipsis, and lays it out again.
+func (l *layouter) ellipsize() *Result {
+  if len(l.result.Lines) == 0 {
+    return l.result
+  }
+  line := l.result.Lines[len(l.result.Lines)-1]
+
+  // the ellipsis takes the style of the last rune on the line
+  last := line.Start
+  if line.End > line.Start {
+    last = line.End - 1
+  }
+  style := 0
+  atlas, scale := l.atlas, l.scale
+  if l.styles != nil && last < len(l.styles) {
+    style = l.styles[last]
+    atlas, scale = l.style(style)
+  }
+  ellipsis := []rune(l.opts.Ellipsis)
+  if len(ellipsis) == 0 {
+    ellipsis = []rune("...")
+    if _, ok := atlas.Items[0x2026]; ok {
+      ellipsis = []rune{0x2026}
+    }
+  }
+
+  // without a width the line can grow to fit the ellipsis
+  budget := line.Width
+  if l.opts.Width > 0 {
+    budget = l.opts.Width - atlas.MeasureWidth(string(ellipsis), float32(atlas.FontPt)*scale)
+  }
+
+  // head returns the end of the longest run of whole grapheme clusters from
+  // the start of the line that fits in width, without trailing whitespace.
+  head := func(width float32) int {
+    end := l.trimEnd(line.Start, line.End)
+    for end > line.Start && l.measure(line.Start, end) > width {
+      end = l.trimEnd(line.Start, grapheme.Prev(l.runes, end))
+    }
+    return end
+  }
+  // tail returns the start of the longest run of whole grapheme clusters
+  // up to the end of the text that fits in width, on one line and not
+  // before from, without leading whitespace.
+  tail := func(from int, width float32) int {
+    start := len(l.runes)
+    for start > from && !mandatoryBreak(l.runes[start-1]) {
+      start--
+    }
+    for {
+      for start < len(l.runes) && isSpace(l.runes[start]) {
+        start++
+      }
+      if start == len(l.runes) || l.measure(start, len(l.runes)) <= width {
+        return start
+      }
+      start = grapheme.Next(l.runes, start)
+    }
+  }
+
+  var a, b int
+  switch l.opts.Overflow {
+  case OverflowEllipsisEnd:
+    a, b = head(budget), len(l.runes)
+  case OverflowEllipsisMiddle:
+    a = head(budget / 2)
+    b = tail(a, budget-l.measure(line.Start, a))
+  case OverflowEllipsisStart:
+    a = line.Start
+    b = tail(a, budget)
+  }
+
+  opts := l.opts
+  opts.Overflow = OverflowClip
+  for {
+    // an ellipsis starting the line must not pull the end of the line
+    // before it along, as "..." would after a space, so a zero width space
+    // guarantees a break opportunity
+    mark := ellipsis
+    if a == line.Start && a > 0 && !mandatoryBreak(l.runes[a-1]) {
+      mark = append([]rune{0x200B}, ellipsis...)
+    }
+    runes := make([]rune, 0, a+len(mark)+len(l.runes)-b)
+    runes = append(append(append(runes, l.runes[:a]...), mark...), l.runes[b:]...)
+    var styles []int
+    if l.styles != nil {
+      styles = make([]int, 0, len(runes))
+      styles = append(styles, l.styles[:a]...)
+      for range mark {
+        styles = append(styles, style)
+      }
+      styles = append(styles, l.styles[b:]...)
+    }
+    e := newLayouter(l.atlas, runes, styles, l.spans, opts)
+    e.run()
+    // rounding or a break before the ellipsis can still overflow; give up
+    // whole clusters until it fits, or clip if the ellipsis alone doesn't
+    switch {
+    case !e.result.Overflow:
+    case a > line.Start:
+      a = l.trimEnd(line.Start, grapheme.Prev(l.runes, a))
+      continue
+    case b < len(l.runes):
+      b = grapheme.Next(l.runes, b)
+      continue
+    default:
+      return l.result
+    }
+    return l.unmap(e.result, a, b, len(mark))
+  }
+}
+
+// unmap maps a result laid out with the runes [a, b) replaced by n runes of
+// ellipsis back to the original runes.
+func (l *layouter) unmap(r *Result, a, b, n int) *Result {
+  index := func(i int) int {
+    switch {
+    case i <= a:
+      return i
+    case i < a+n:
+      return a
+    }
+    return i - n + b - a
+  }
+  for i := range r.Glyphs {
+    r.Glyphs[i].Index = index(r.Glyphs[i].Index)
+  }
+  for i := range r.Lines {
+    r.Lines[i].Start = index(r.Lines[i].Start)
+    r.Lines[i].End = index(r.Lines[i].End)
+  }
+
+  edges := make([]edge, len(l.runes))
+  copy(edges, r.edges[:a])
+  copy(edges[b:], r.edges[a+n:])
+  if n > 0 {
+    span := r.edges[a+n-1]
+    for _, e := range r.edges[a : a+n] {
+      if e.right <= e.left {
+        continue
+      }
+      if span.right <= span.left {
+        span = e
+      }
+      if e.left < span.left {
+        span.left = e.left
+      }
+      if e.right > span.right {
+        span.right = e.right
+      }
+    }
+    for k := a; k < b; k++ {
+      edges[k] = span
+    }
+  }
+
+  r.End = index(r.End)
+  r.runes, r.edges = l.runes, edges
+  r.Overflow, r.Truncated = true, true
+  r.Elided = [2]int{a, b}
+  return r
+}
@@ -0,0 +1,54 @@
+package layout
+
+import "testing"
+
+func TestOverflow(t *testing.T) {
+  atlas := testAtlas(t)
+  const width = 200
+  lineHeight := Layout(atlas, "x", Options{Size: 16}).Height
+  for _, test := range []struct {
+    overflow Overflow
+    elided   func(a, b, n int) bool
+  }{
+    {OverflowEllipsisEnd, func(a, b, n int) bool { return b == n }},
+    {OverflowEllipsisMiddle, func(a, b, n int) bool { return a > 0 && b < n }},
+    {OverflowEllipsisStart, func(a, b, n int) bool { return a == 0 }},
+  } {
+    r := Layout(atlas, testText, Options{Size: 16, Width: width, Height: lineHeight * 1.5, Overflow: test.overflow})
+    n := len([]rune(testText))
+    if !r.Overflow || !r.Truncated || len(r.Lines) != 1 {
+      t.Errorf("overflow %d: Overflow %v, Truncated %v, %d lines", test.overflow, r.Overflow, r.Truncated, len(r.Lines))
+      continue
+    }
+    if a, b := r.Elided[0], r.Elided[1]; a >= b || !test.elided(a, b, n) {
+      t.Errorf("overflow %d: elided [%d, %d) of %d runes", test.overflow, a, b, n)
+    }
+    if r.Lines[0].Width > width+0.01 {
+      t.Errorf("overflow %d: line is %v wide", test.overflow, r.Lines[0].Width)
+    }
+    ellipsis := 0
+    for _, g := range r.Glyphs {
+      if g.Rune == '…' {
+        ellipsis++
+      }
+    }
+    if ellipsis != 1 {
+      t.Errorf("overflow %d: %d ellipses", test.overflow, ellipsis)
+    }
+  }
+
+  r := Layout(atlas, testText, Options{Size: 16, Width: width, Height: lineHeight * 1.5})
+  if !r.Overflow || r.Truncated || len(r.Lines) != 1 || r.End != r.Lines[0].End {
+    t.Errorf("clip: Overflow %v, Truncated %v, %d lines ending at %d, End %d", r.Overflow, r.Truncated, len(r.Lines), r.Lines[0].End, r.End)
+  }
+
+  const height = 40
+  r = Layout(atlas, testText, Options{Size: 16, Width: width, Height: height, Overflow: OverflowShrink})
+  if r.Overflow || r.Size >= 16 || r.Size < 4 || r.Height > height || r.End != len([]rune(testText)) {
+    t.Errorf("shrink: Overflow %v, size %v, height %v, End %d", r.Overflow, r.Size, r.Height, r.End)
+  }
+  r = Layout(atlas, "short", Options{Size: 16, Width: width, Height: height, Overflow: OverflowShrink})
+  if r.Size != 16 {
+    t.Errorf("shrink: text that fits shrank to %v", r.Size)
+  }
+}
@@ -0,0 +1,168 @@
+package layout
+
+import (
+  "image/color"
+
+  "github.com/vrav/ratlas"
+)
+
+// Style is how a span of text is drawn. The zero Style draws in the atlas
+// and size passed to LayoutSpans.
+type Style struct {
+  // Atlas is the font of the span, nil for the default.
+  Atlas *ratlas.Atlas
+
+  // Scale multiplies Options.Size; zero means 1.
+  Scale float32
+
+  // Color is the color of the span, nil for the caller's default. Layout
+  // doesn't use it; renderers look it up through Glyph.Span.
+  Color color.Color
+
+  // Underline adds the span's underlines to Result.Underlines.
+  Underline bool
+}
+
+// Span is a run of text drawn in one style.
+type Span struct {
+  Text  string
+  Style Style
+}
+
+// Underline is an underline to draw below a span on one line.
+type Underline struct {
+  ratlas.Bounds
+  Span int
+}
+
+// LayoutSpans lays out the concatenated text of spans in the box described
+// by opts, with atlas as the font of spans that don't set their own. Rune
+// indices in the Result refer to the concatenated text.
+func LayoutSpans(atlas *ratlas.Atlas, spans []Span, opts Options) *Result {
+  runes := []rune{}
+  styles := []int{}
+  for i, span := range spans {
+    for _, r := range span.Text {
+      runes = append(runes, r)
+      styles = append(styles, i)
+    }
+  }
+  return layout(atlas, runes, styles, spans, opts)
+}
+
+// style returns the atlas and scale of the span at index s.
+func (l *layouter) style(s int) (*ratlas.Atlas, float32) {
+  style := l.spans[s].Style
+  atlas := l.atlas
+  if style.Atlas != nil {
+    atlas = style.Atlas
+  }
+  size := l.opts.Size
+  if style.Scale > 0 {
+    size *= style.Scale
+  }
+  return atlas, size / float32(atlas.FontPt)
+}
+
+// font returns the atlas and scale rune i is drawn with.
+func (l *layouter) font(i int) (*ratlas.Atlas, float32) {
+  if l.styles == nil {
+    return l.atlas, l.scale
+  }
+  return l.style(l.styles[i])
+}
+
+// hyphenAt returns the hyphen drawn after rune i, from its font if it has
+// one and from the default atlas otherwise.
+func (l *layouter) hyphenAt(i int) placed {
+  atlas, scale := l.font(i)
+  if atlas == l.atlas {
+    return placed{r: l.hyphenRune, item: l.hyphen, atlas: atlas, scale: scale}
+  }
+  for _, r := range []rune{'-', 0x2010} {
+    if item, ok := atlas.Items[r]; ok {
+      return placed{r: r, item: item, atlas: atlas, scale: scale}
+    }
+  }
+  return placed{r: l.hyphenRune, item: l.hyphen, atlas: l.atlas, scale: l.scale}
+}
+
+// metrics returns the ascent, descent and line gap of the line holding runes
+// [start, end), the largest of its fonts'. The gap can be negative for fonts
+// whose lines overlap. Empty lines use the font of their
+// newline, or of the rune before at the end of the text.
+func (l *layouter) metrics(start, end int) (ascent, descent, gap float32) {
+  if start == end {
+    if start == len(l.runes) {
+      start--
+    }
+    end = start + 1
+  }
+  for i := start; i < end; i++ {
+    atlas, scale := l.atlas, l.scale
+    if i >= 0 {
+      atlas, scale = l.font(i)
+    }
+    a, d := atlas.Ascent()*scale, atlas.Descent()*scale
+    g := atlas.Height()*scale - a - d
+    if i == start {
+      ascent, descent, gap = a, d, g
+    }
+    if a > ascent {
+      ascent = a
+    }
+    if d > descent {
+      descent = d
+    }
+    if g > gap {
+      gap = g
+    }
+  }
+  return ascent, descent, gap
+}
+
+// underline adds the underlines of the laid out lines to the result.
+func (l *layouter) underline() {
+  r := l.result
+  if l.styles == nil {
+    return
+  }
+  for _, line := range r.Lines {
+    first := len(r.Underlines)
+    for k := line.Start; k < l.trimEnd(line.Start, line.End); k++ {
+      s, e := l.styles[k], r.edges[k]
+      if !l.spans[s].Style.Underline || e.right <= e.left {
+        continue
+      }
+      // extend a touching underline of the same span, as runes of a span
+      // are adjacent but for bidi reordering
+      extended := false
+      for j := first; j < len(r.Underlines); j++ {
+        u := &r.Underlines[j]
+        if u.Span == s && e.left <= u.X1+0.5 && e.right >= u.X0-0.5 {
+          if e.left < u.X0 {
+            u.X0 = e.left
+          }
+          if e.right > u.X1 {
+            u.X1 = e.right
+          }
+          extended = true
+          break
+        }
+      }
+      if extended {
+        continue
+      }
+      atlas, scale := l.font(k)
+      size := float32(atlas.FontPt) * scale
+      u := Underline{Span: s}
+      u.X0, u.X1 = e.left, e.right
+      u.Y0 = line.Y + size/10
+      u.Y1 = u.Y0 + size/16
+      if u.Y1 < u.Y0+1 {
+        u.Y1 = u.Y0 + 1
+      }
+      r.Underlines = append(r.Underlines, u)
+    }
+  }
+}
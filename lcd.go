@@ -0,0 +1,120 @@
+package ratlas
+
+import (
+  "image"
+  "image/draw"
+
+  "github.com/golang/freetype/truetype"
+  "golang.org/x/image/vector"
+)
+
+// LCDOrder is the order of the subpixels of an LCD screen.
+type LCDOrder int
+
+const (
+  // LCDNone bakes grayscale coverage.
+  LCDNone LCDOrder = iota
+  LCDRGB
+  LCDBGR
+  
+  // LCDVRGB and LCDVBGR are screens with horizontal subpixel stripes, red on
+  // top for LCDVRGB.
+  LCDVRGB
+  LCDVBGR
+)
+
+// LCD configures subpixel antialiased atlases for LCD screens. Glyphs are
+// rasterized at three times the resolution across subpixels, filtered, and
+// stored as per-channel coverage on *image.NRGBA pages: red, green and blue
+// coverage in their own channels whatever the screen's order, and the
+// greatest of them in alpha for blending without per-channel coverage.
+type LCD struct {
+  Order LCDOrder
+  
+  // Filter spreads the coverage of each subpixel over its neighbours,
+  // centered on it, to tame color fringes; its weights should add up to 1.
+  // Zero means FreeType's default filter.
+  Filter [5]float32
+}
+
+var defaultLCDFilter = [5]float32{0x08 / 256.0, 0x4D / 256.0, 0x56 / 256.0, 0x4D / 256.0, 0x08 / 256.0}
+
+// vertical reports whether subpixels are stacked vertically.
+func (lcd LCD) vertical() bool {
+  return lcd.Order == LCDVRGB || lcd.Order == LCDVBGR
+}
+
+// drawLCD renders a glyph index with LCD coverage into rect of dst, placed
+// as drawGlyph places it.
+func (atlas *Atlas) drawLCD(dst *image.NRGBA, rect image.Rectangle, index truetype.Index, pad int, phase float32) error {
+  glyphBuf, err := atlas.loadGlyph(index)
+  if err != nil {
+    return err
+  }
+  dx, dy := glyphOrigin(glyphBuf, pad, phase)
+  
+  // rasterize at three times the resolution across subpixels
+  w, h := rect.Dx(), rect.Dy()
+  sx, sy := float32(3), float32(1)
+  if atlas.LCD.vertical() {
+    sx, sy = 1, 3
+  }
+  hw, hh := w*int(sx), h*int(sy)
+  hi := image.NewAlpha(image.Rect(0, 0, hw, hh))
+  r := vector.NewRasterizer(hw, hh)
+  e0 := 0
+  for _, e1 := range glyphBuf.Ends {
+    drawContour(scaledPather{r, sx, sy}, glyphBuf.Points[e0:e1], dx, dy)
+    e0 = e1
+  }
+  r.Draw(hi, hi.Bounds(), image.Opaque, image.Point{})
+  if atlas.dilation() > 0 {
+    bold := image.NewAlpha(hi.Bounds())
+    atlas.strokeGlyph(glyphBuf, bold, dx, dy, sx, sy, atlas.dilation(), JoinRound)
+    draw.DrawMask(hi, hi.Bounds(), image.Opaque, image.Point{}, bold, image.Point{}, draw.Over)
+  }
+  
+  filter := atlas.LCD.Filter
+  if filter == ([5]float32{}) {
+    filter = defaultLCDFilter
+  }
+  table := atlas.Coverage.table()
+  at := func(x, y int) float32 {
+    if x < 0 || y < 0 || x >= hw || y >= hh {
+      return 0
+    }
+    return float32(hi.Pix[y*hi.Stride+x]) / 255
+  }
+  for y := 0; y < h; y++ {
+    for x := 0; x < w; x++ {
+      i := dst.PixOffset(rect.Min.X+x, rect.Min.Y+y)
+      var alpha uint8
+      for c := 0; c < 3; c++ {
+        var v float32
+        for k, weight := range filter {
+          if atlas.LCD.vertical() {
+            v += weight * at(x, 3*y+c+k-2)
+          } else {
+            v += weight * at(3*x+c+k-2, y)
+          }
+        }
+        if v > 1 {
+          v = 1
+        }
+        channel := c
+        if atlas.LCD.Order == LCDBGR || atlas.LCD.Order == LCDVBGR {
+          channel = 2 - c
+        }
+        dst.Pix[i+channel] = uint8(v*255 + 0.5)
+        if table != nil {
+          dst.Pix[i+channel] = table[dst.Pix[i+channel]]
+        }
+        if dst.Pix[i+channel] > alpha {
+          alpha = dst.Pix[i+channel]
+        }
+      }
+      dst.Pix[i+3] = alpha
+    }
+  }
+  return nil
+}
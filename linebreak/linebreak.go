@@ -0,0 +1,455 @@
+// Package linebreak finds line break opportunities following the Unicode Line
+// Breaking Algorithm (UAX #14), including the tailorable rules in their
+// default form. Complex-context scripts (class SA, such as Thai) are treated
+// as alphabetic, since breaking them needs a dictionary.
+package linebreak
+
+import (
+  "sort"
+  "unicode"
+)
+
+// Class is a Unicode line breaking class.
+type Class uint8
+
+const (
+  XX Class = iota // Unknown
+  AI              // Ambiguous
+  AK              // Aksara
+  AL              // Alphabetic
+  AP              // Aksara_Prebase
+  AS              // Aksara_Start
+  B2              // Break_Both
+  BA              // Break_After
+  BB              // Break_Before
+  BK              // Mandatory_Break
+  CB              // Contingent_Break
+  CJ              // Conditional_Japanese_Starter
+  CL              // Close_Punctuation
+  CM              // Combining_Mark
+  CP              // Close_Parenthesis
+  CR              // Carriage_Return
+  EB              // E_Base
+  EM              // E_Modifier
+  EX              // Exclamation
+  GL              // Glue
+  H2              // H2
+  H3              // H3
+  HH              // Unambiguous_Hyphen
+  HL              // Hebrew_Letter
+  HY              // Hyphen
+  ID              // Ideographic
+  IN              // Inseparable
+  IS              // Infix_Numeric
+  JL              // JL
+  JT              // JT
+  JV              // JV
+  LF              // Line_Feed
+  NL              // Next_Line
+  NS              // Nonstarter
+  NU              // Numeric
+  OP              // Open_Punctuation
+  PO              // Postfix_Numeric
+  PR              // Prefix_Numeric
+  QU              // Quotation
+  RI              // Regional_Indicator
+  SA              // Complex_Context
+  SG              // Surrogate
+  SP              // Space
+  SY              // Break_Symbols
+  VF              // Virama_Final
+  VI              // Virama
+  WJ              // Word_Joiner
+  ZW              // ZWSpace
+  ZWJ             // ZWJ
+)
+
+type classRange struct {
+  lo, hi rune
+  class  Class
+}
+
+type runeRange struct {
+  lo, hi rune
+}
+
+// ClassOf returns the line breaking class of r as listed by Unicode, before
+// the resolution of rule LB1.
+func ClassOf(r rune) Class {
+  i := sort.Search(len(classRanges), func(i int) bool { return classRanges[i].hi >= r })
+  if i < len(classRanges) && classRanges[i].lo <= r {
+    return classRanges[i].class
+  }
+  return XX
+}
+
+func inRanges(ranges []runeRange, r rune) bool {
+  i := sort.Search(len(ranges), func(i int) bool { return ranges[i].hi >= r })
+  return i < len(ranges) && ranges[i].lo <= r
+}
+
+// resolve applies rule LB1.
+func resolve(r rune, c Class) Class {
+  switch c {
+  case AI, SG, XX:
+    return AL
+  case SA:
+    if unicode.In(r, unicode.Mn, unicode.Mc) {
+      return CM
+    }
+    return AL
+  case CJ:
+    return NS
+  }
+  return c
+}
+
+// Break is the kind of line break opportunity at a position.
+type Break uint8
+
+const (
+  // Prohibited means the line must not break here.
+  Prohibited Break = iota
+  // Allowed means the line may break here.
+  Allowed
+  // Mandatory means the line must break here, after a newline or similar.
+  Mandatory
+)
+
+// dottedCircle is U+25CC, which rule LB28a treats like an aksara.
+const dottedCircle = 0x25CC
+
+// breaker holds the resolved classes of the text being broken.
+type breaker struct {
+  runes []rune
+
+  // raw is the class after LB1 and class the class of the combining
+  // sequence a rune belongs to, after LB9 and LB10.
+  raw   []Class
+  class []Class
+
+  // base is the index of the first rune of the combining sequence.
+  base []int
+}
+
+// Breaks returns the break opportunity before each rune of text, plus one
+// for the end of text, which is always Mandatory. Breaks[i] is the
+// opportunity between runes[i-1] and runes[i]; Breaks[0] is Prohibited.
+func Breaks(runes []rune) []Break {
+  n := len(runes)
+  b := &breaker{
+    runes: runes,
+    raw:   make([]Class, n),
+    class: make([]Class, n),
+    base:  make([]int, n),
+  }
+  for i, r := range runes {
+    b.raw[i] = resolve(r, ClassOf(r))
+    b.class[i] = b.raw[i]
+    b.base[i] = i
+    if b.raw[i] != CM && b.raw[i] != ZWJ {
+      continue
+    }
+    // LB9: X (CM | ZWJ)* is treated as X; LB10: otherwise as AL
+    if i > 0 {
+      switch b.class[i-1] {
+      case BK, CR, LF, NL, SP, ZW:
+      default:
+        b.class[i] = b.class[i-1]
+        b.base[i] = b.base[i-1]
+        continue
+      }
+    }
+    b.class[i] = AL
+  }
+
+  breaks := make([]Break, n+1)
+  for i := 1; i < n; i++ {
+    breaks[i] = b.at(i)
+  }
+  breaks[n] = Mandatory
+  return breaks
+}
+
+// before returns the class of the combining sequence before the one holding
+// rune i, or XX at the start of text.
+func (b *breaker) before(i int) (Class, int) {
+  j := b.base[i] - 1
+  if j < 0 {
+    return XX, -1
+  }
+  return b.class[j], j
+}
+
+// after returns the class of the combining sequence following the one that
+// starts at rune i, or XX at the end of text.
+func (b *breaker) after(i int) (Class, int) {
+  j := i + 1
+  for j < len(b.runes) && b.base[j] != j {
+    j++
+  }
+  if j == len(b.runes) {
+    return XX, j
+  }
+  return b.class[j], j
+}
+
+// beforeSpaces returns the class of the sequence ending at rune i, skipping
+// back over spaces, or XX at the start of text.
+func (b *breaker) beforeSpaces(i int) (Class, int) {
+  for i >= 0 && b.class[i] == SP {
+    i--
+  }
+  if i < 0 {
+    return XX, -1
+  }
+  return b.class[i], i
+}
+
+// eastAsian reports whether the sequence holding rune i is East Asian
+// (East_Asian_Width F, W or H).
+func (b *breaker) eastAsian(i int) bool {
+  return inRanges(eastAsian, b.runes[b.base[i]])
+}
+
+// aksara reports whether the sequence holding rune i is AK, AS or a dotted circle.
+func (b *breaker) aksara(i int) bool {
+  c := b.class[i]
+  return c == AK || c == AS || b.runes[b.base[i]] == dottedCircle
+}
+
+func is(c Class, classes ...Class) bool {
+  for _, x := range classes {
+    if c == x {
+      return true
+    }
+  }
+  return false
+}
+
+// at returns the break opportunity between runes i-1 and i.
+func (b *breaker) at(i int) Break {
+  a, c := b.class[i-1], b.class[i]
+  quote := func(j int, cat *unicode.RangeTable) bool {
+    return b.class[j] == QU && unicode.Is(cat, b.runes[b.base[j]])
+  }
+
+  // LB4, LB5: mandatory breaks
+  if a == BK {
+    return Mandatory
+  }
+  if a == CR && c == LF {
+    return Prohibited
+  }
+  if is(a, CR, LF, NL) {
+    return Mandatory
+  }
+  // LB6, LB7
+  if is(c, BK, CR, LF, NL, SP, ZW) {
+    return Prohibited
+  }
+  // LB8: ZW SP* ÷
+  if prev, _ := b.beforeSpaces(i - 1); prev == ZW {
+    return Allowed
+  }
+  // LB8a: ZWJ ×
+  if b.raw[i-1] == ZWJ {
+    return Prohibited
+  }
+  // LB9: combining marks stay with their base
+  if b.base[i] != i {
+    return Prohibited
+  }
+  // LB11
+  if a == WJ || c == WJ {
+    return Prohibited
+  }
+  // LB12, LB12a
+  if a == GL || c == GL && !is(a, SP, BA, HY, HH) {
+    return Prohibited
+  }
+  // LB13
+  if is(c, CL, CP, EX, SY) {
+    return Prohibited
+  }
+  // LB14: OP SP* ×
+  prev, p := b.beforeSpaces(i - 1)
+  if prev == OP {
+    return Prohibited
+  }
+  // LB15a: (sot | BK | CR | LF | NL | OP | QU | GL | SP | ZW) [QU & Pi] SP* ×
+  if p >= 0 && quote(p, unicode.Pi) {
+    if bc, j := b.before(p); j < 0 || is(bc, BK, CR, LF, NL, OP, QU, GL, SP, ZW) {
+      return Prohibited
+    }
+  }
+  // LB15b: × [QU & Pf] (SP | GL | WJ | CL | QU | CP | EX | IS | SY | BK | CR | LF | NL | ZW | eot)
+  if quote(i, unicode.Pf) {
+    if ac, j := b.after(i); j == len(b.runes) || is(ac, SP, GL, WJ, CL, QU, CP, EX, IS, SY, BK, CR, LF, NL, ZW) {
+      return Prohibited
+    }
+  }
+  // LB15c, LB15d
+  if c == IS {
+    if ac, _ := b.after(i); a == SP && ac == NU {
+      return Allowed
+    }
+    return Prohibited
+  }
+  // LB16: (CL | CP) SP* × NS
+  if c == NS && is(prev, CL, CP) {
+    return Prohibited
+  }
+  // LB17: B2 SP* × B2
+  if c == B2 && prev == B2 {
+    return Prohibited
+  }
+  // LB18
+  if a == SP {
+    return Allowed
+  }
+  // LB19
+  if c == QU && !quote(i, unicode.Pi) || a == QU && !quote(i-1, unicode.Pf) {
+    return Prohibited
+  }
+  // LB19a
+  if c == QU {
+    if !b.eastAsian(i - 1) {
+      return Prohibited
+    }
+    if _, j := b.after(i); j == len(b.runes) || !b.eastAsian(j) {
+      return Prohibited
+    }
+  }
+  if a == QU {
+    if !b.eastAsian(i) {
+      return Prohibited
+    }
+    if _, j := b.before(i - 1); j < 0 || !b.eastAsian(j) {
+      return Prohibited
+    }
+  }
+  // LB20
+  if a == CB || c == CB {
+    return Allowed
+  }
+  // LB20a: (sot | BK | CR | LF | NL | SP | ZW | CB | GL) (HY | HH) × (AL | HL)
+  if is(a, HY, HH) && is(c, AL, HL) {
+    if bc, j := b.before(i - 1); j < 0 || is(bc, BK, CR, LF, NL, SP, ZW, CB, GL) {
+      return Prohibited
+    }
+  }
+  // LB21
+  if is(c, BA, HH, HY, NS) || a == BB {
+    return Prohibited
+  }
+  // LB21a: HL (HY | HH) × [^HL]
+  if is(a, HY, HH) && c != HL {
+    if bc, _ := b.before(i - 1); bc == HL {
+      return Prohibited
+    }
+  }
+  // LB21b
+  if a == SY && c == HL {
+    return Prohibited
+  }
+  // LB22
+  if c == IN {
+    return Prohibited
+  }
+  // LB23, LB23a, LB24
+  if is(a, AL, HL) && c == NU || a == NU && is(c, AL, HL) {
+    return Prohibited
+  }
+  if a == PR && is(c, ID, EB, EM) || is(a, ID, EB, EM) && c == PO {
+    return Prohibited
+  }
+  if is(a, PR, PO) && is(c, AL, HL) || is(a, AL, HL) && is(c, PR, PO) {
+    return Prohibited
+  }
+  // LB25: numbers
+  if is(c, PO, PR, NU) {
+    j := i - 1
+    if is(c, PO, PR) && is(a, CL, CP) {
+      _, j = b.before(i - 1)
+    }
+    for j >= 0 && is(b.class[j], SY, IS) {
+      _, j = b.before(j)
+    }
+    if j >= 0 && b.class[j] == NU {
+      return Prohibited
+    }
+  }
+  if is(a, PO, PR) {
+    if c == NU {
+      return Prohibited
+    }
+    if c == OP {
+      ac, j := b.after(i)
+      if ac == IS {
+        ac, _ = b.after(j)
+      }
+      if ac == NU {
+        return Prohibited
+      }
+    }
+  }
+  if is(a, HY, IS) && c == NU {
+    return Prohibited
+  }
+  // LB26, LB27: Korean syllable blocks
+  if a == JL && is(c, JL, JV, H2, H3) || is(a, JV, H2) && is(c, JV, JT) || is(a, JT, H3) && c == JT {
+    return Prohibited
+  }
+  if is(a, JL, JV, JT, H2, H3) && c == PO || a == PR && is(c, JL, JV, JT, H2, H3) {
+    return Prohibited
+  }
+  // LB28
+  if is(a, AL, HL) && is(c, AL, HL) {
+    return Prohibited
+  }
+  // LB28a: Brahmic orthographic syllables
+  if a == AP && b.aksara(i) {
+    return Prohibited
+  }
+  if b.aksara(i-1) && is(c, VF, VI) {
+    return Prohibited
+  }
+  if a == VI && (c == AK || b.runes[i] == dottedCircle) {
+    if _, j := b.before(i - 1); j >= 0 && b.aksara(j) {
+      return Prohibited
+    }
+  }
+  if b.aksara(i-1) && b.aksara(i) {
+    if ac, _ := b.after(i); ac == VF {
+      return Prohibited
+    }
+  }
+  // LB29
+  if a == IS && is(c, AL, HL) {
+    return Prohibited
+  }
+  // LB30
+  if is(a, AL, HL, NU) && c == OP && !b.eastAsian(i) {
+    return Prohibited
+  }
+  if a == CP && !b.eastAsian(i-1) && is(c, AL, HL, NU) {
+    return Prohibited
+  }
+  // LB30a: pairs of regional indicators
+  if a == RI && c == RI {
+    count := 0
+    for j := i - 1; j >= 0 && b.class[j] == RI; _, j = b.before(j) {
+      count++
+    }
+    if count%2 == 1 {
+      return Prohibited
+    }
+  }
+  // LB30b
+  if c == EM && (a == EB || inRanges(unassignedPictographic, b.runes[b.base[i-1]])) {
+    return Prohibited
+  }
+  // LB31
+  return Allowed
+}
@@ -0,0 +1,62 @@
+package linebreak
+
+import (
+  "bufio"
+  "os"
+  "strconv"
+  "strings"
+  "testing"
+)
+
+// TestConformance runs a subset of LineBreakTest.txt of the Unicode version
+// of the tables: "÷" marks a break opportunity and "×" none, between
+// hexadecimal runes. The start of text is never a break.
+func TestConformance(t *testing.T) {
+  f, err := os.Open("testdata/LineBreakTest.txt")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer f.Close()
+  scanner := bufio.NewScanner(f)
+  tests := 0
+  for n := 1; scanner.Scan(); n++ {
+    line := scanner.Text()
+    if i := strings.IndexByte(line, '#'); i >= 0 {
+      line = line[:i]
+    }
+    fields := strings.Fields(line)
+    if len(fields) == 0 {
+      continue
+    }
+    var runes []rune
+    var want []bool
+    for _, field := range fields {
+      switch field {
+      case "÷", "×":
+        want = append(want, field == "÷")
+      default:
+        v, err := strconv.ParseUint(field, 16, 32)
+        if err != nil {
+          t.Fatalf("line %d: %v", n, err)
+        }
+        runes = append(runes, rune(v))
+      }
+    }
+    breaks := Breaks(runes)
+    for i := 1; i < len(want); i++ {
+      if got := breaks[i] != Prohibited; got != want[i] {
+        t.Errorf("line %d: %s: break before rune %d is %v", n, strings.TrimSpace(line), i, breaks[i])
+      }
+    }
+    if breaks[len(runes)] != Mandatory {
+      t.Errorf("line %d: end of text is %v", n, breaks[len(runes)])
+    }
+    tests++
+  }
+  if err := scanner.Err(); err != nil {
+    t.Fatal(err)
+  }
+  if tests == 0 {
+    t.Fatal("no tests")
+  }
+}
@@ -0,0 +1,167 @@
+package ratlas
+
+// Bounds is a rectangle in pixels, with Y growing downwards.
+type Bounds struct {
+  X0, Y0, X1, Y1 float32
+}
+
+// Metrics describes the size of a string drawn at a font size.
+// Lines are split at newlines and not wrapped; text is measured in logical
+// order, which matches package layout for left-to-right text.
+type Metrics struct {
+  // Advance is the pen advance of the widest line, kerning included and
+  // trailing whitespace excluded.
+  Advance float32
+
+  // Ink is the box covered by the glyphs, without atlas padding, relative to
+  // the top left of the first line, whose baseline is at Ascent.
+  Ink Bounds
+
+  // Lines is the number of lines, and Height their height from the top of
+  // the first to the bottom of the last.
+  Lines  int
+  Height float32
+
+  // Offsets holds the pen position of each rune of []rune(text) from the
+  // start of its line.
+  Offsets []float32
+}
+
+// Scale returns the factor AtlasItem metrics are scaled by to draw at size.
+func (atlas *Atlas) Scale(size float32) float32 {
+  return size / float32(atlas.FontPt)
+}
+
+// Measure returns the Metrics of text drawn at size.
+func (atlas *Atlas) Measure(text string, size float32) Metrics {
+  runes := []rune(text)
+  metrics := Metrics{Offsets: make([]float32, len(runes))}
+  atlas.measure(runes, size, &metrics)
+  return metrics
+}
+
+// MeasureWidth returns the pen advance of the widest line of text drawn at size.
+func (atlas *Atlas) MeasureWidth(text string, size float32) float32 {
+  var metrics Metrics
+  atlas.measure([]rune(text), size, &metrics)
+  return metrics.Advance
+}
+
+// Offsets returns the pen position of each rune of text drawn at size, from
+// the start of its line.
+func (atlas *Atlas) Offsets(text string, size float32) []float32 {
+  return atlas.Measure(text, size).Offsets
+}
+
+// invisible reports whether r takes no advance and is never drawn, as in
+// package layout. Tabs advance to the next stop, every 8 spaces.
+func invisible(r rune) bool {
+  switch r {
+  case '\t', 0x00AD, 0x200B, 0x200C, 0x200D, 0x2060, 0xFEFF:
+    return true
+  }
+  return false
+}
+
+func (atlas *Atlas) drawnItem(r rune) (*AtlasItem, bool) {
+  if invisible(r) {
+    return nil, false
+  }
+  atlasItem, ok := atlas.Items[r]
+  return atlasItem, ok
+}
+
+// lineBreak reports whether r ends a line, as '\n' and the mandatory breaks
+// of UAX #14 do in package layout. A '\r' followed by '\n' doesn't.
+func lineBreak(runes []rune, i int) bool {
+  switch runes[i] {
+  case '\n', 0x0B, 0x0C, 0x85, 0x2028, 0x2029:
+    return true
+  case '\r':
+    return i+1 == len(runes) || runes[i+1] != '\n'
+  }
+  return false
+}
+
+// hangs reports whether r is whitespace that hangs past the end of a line
+// instead of counting towards its width.
+func hangs(r rune) bool {
+  switch r {
+  case ' ', '\t', '\r', 0x0B, 0x0C, 0x85, 0x200B, 0x2028, 0x2029:
+    return true
+  }
+  return false
+}
+
+func (atlas *Atlas) measure(runes []rune, size float32, metrics *Metrics) {
+  scale := atlas.Scale(size)
+  ascent := atlas.Ascent() * scale
+  lineHeight := atlas.Height() * scale
+  pad := float32(atlas.Pad)
+
+  tabWidth := 2 * size
+  if space, ok := atlas.Items[' ']; ok {
+    tabWidth = 8 * space.Advance * scale
+  }
+
+  metrics.Lines = 1
+  var pen float32
+  var prev *AtlasItem
+  inked := false
+  for i, r := range runes {
+    atlasItem, ok := atlas.drawnItem(r)
+    if ok && prev != nil {
+      pen += atlas.Kern(runes[i-1], r) * scale
+    }
+    if metrics.Offsets != nil {
+      metrics.Offsets[i] = pen
+    }
+    prev = atlasItem
+
+    switch {
+    case lineBreak(runes, i):
+      metrics.Lines++
+      pen = 0
+      continue
+    case r == '\t':
+      pen = float32(int(pen/tabWidth)+1) * tabWidth
+    case ok:
+      if atlasItem.Width > int(2*pad) && atlasItem.Height > int(2*pad) {
+        y := ascent + float32(metrics.Lines-1)*lineHeight
+        ink := Bounds{
+          X0: pen + (atlasItem.BearingX+pad)*scale,
+          Y1: y + (atlasItem.Descent-pad)*scale,
+        }
+        ink.X1 = ink.X0 + (float32(atlasItem.Width)-2*pad)*scale
+        ink.Y0 = ink.Y1 - (float32(atlasItem.Height)-2*pad)*scale
+        if !inked {
+          metrics.Ink, inked = ink, true
+        } else {
+          metrics.Ink = metrics.Ink.union(ink)
+        }
+      }
+      pen += atlasItem.Advance * scale
+    }
+    if pen > metrics.Advance && !hangs(r) {
+      metrics.Advance = pen
+    }
+  }
+
+  metrics.Height = ascent + atlas.Descent()*scale + float32(metrics.Lines-1)*lineHeight
+}
+
+func (b Bounds) union(o Bounds) Bounds {
+  if o.X0 < b.X0 {
+    b.X0 = o.X0
+  }
+  if o.Y0 < b.Y0 {
+    b.Y0 = o.Y0
+  }
+  if o.X1 > b.X1 {
+    b.X1 = o.X1
+  }
+  if o.Y1 > b.Y1 {
+    b.Y1 = o.Y1
+  }
+  return b
+}
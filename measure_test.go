@@ -0,0 +1,57 @@
+package ratlas_test
+
+import (
+  "io/ioutil"
+  "math"
+  "testing"
+
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/layout"
+)
+
+func near(a, b float32) bool {
+  return math.Abs(float64(a-b)) < 0.01
+}
+
+// TestMeasure checks that Measure and MeasureWidth agree with package layout
+// on unwrapped left-to-right text.
+func TestMeasure(t *testing.T) {
+  data, err := ioutil.ReadFile("example/Vera.ttf")
+  if err != nil {
+    t.Fatal(err)
+  }
+  var runes []rune
+  for r := rune(' '); r <= '~'; r++ {
+    runes = append(runes, r)
+  }
+  atlas := ratlas.NewWithOptions(&data, runes, ratlas.Options{FontPt: 32, Width: 512, Height: 512, Pad: 2})
+
+  for _, test := range []struct {
+    text string
+    size float32
+  }{
+    {"hello", 16},
+    {"AVATAR Today", 32},
+    {"trailing spaces   ", 16},
+    {"two\nlines, the second longer", 12},
+    {"a\tb\tc", 20},
+    {"", 16},
+  } {
+    m := atlas.Measure(test.text, test.size)
+    r := layout.Layout(&atlas, test.text, layout.Options{Size: test.size})
+    if !near(m.Advance, r.Width) {
+      t.Errorf("%q at %v: Advance %v, layout width %v", test.text, test.size, m.Advance, r.Width)
+    }
+    if w := atlas.MeasureWidth(test.text, test.size); !near(w, m.Advance) {
+      t.Errorf("%q at %v: MeasureWidth %v, Advance %v", test.text, test.size, w, m.Advance)
+    }
+    if m.Lines != len(r.Lines) || !near(m.Height, r.Height) {
+      t.Errorf("%q at %v: %d lines %v high, layout %d lines %v high", test.text, test.size, m.Lines, m.Height, len(r.Lines), r.Height)
+    }
+    for _, g := range r.Glyphs {
+      if !near(m.Offsets[g.Index], g.X) {
+        t.Errorf("%q at %v: rune %d at %v, layout %v", test.text, test.size, g.Index, m.Offsets[g.Index], g.X)
+      }
+    }
+  }
+}
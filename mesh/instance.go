@@ -0,0 +1,187 @@
+package mesh
+
+import (
+  "encoding/binary"
+  "image/color"
+  "math"
+
+  "github.com/vrav/ratlas/layout"
+)
+
+// Instance is one glyph for instanced rendering, where a single quad is
+// drawn once per instance. Its memory layout matches the 36 byte vertex
+// attributes below, so on little-endian machines a []Instance can be
+// uploaded as is; Instances.Bytes encodes it anywhere.
+//
+//  offset  type         attribute
+//  0       4 x float32  X, Y, W, H
+//  16      float32      Scale
+//  20      4 x uint16   U0, V0, U1, V1, unsigned normalized
+//  28      4 x uint8    Color, unsigned normalized, straight alpha
+//  32      uint32       Page
+//
+// (X, Y) is the corner at (U0, V0), the glyph's top left, in the output
+// coordinates of Options, and (X+W, Y+H) the opposite corner; H is negative
+// when y grows up. UVs must be normalized, as atlases make them by default:
+// they're clamped to 0..1, so an atlas with UVUnits of ratlas.UVPixels needs
+// Atlas.SetUVConvention(origin, ratlas.UVNormalized) first. See
+// InstanceVertexShader.
+type Instance struct {
+  X, Y, W, H     float32
+  Scale          float32
+  U0, V0, U1, V1 uint16
+  Color          color.NRGBA
+  Page           uint32
+}
+
+// InstanceSize is the size of an Instance in bytes.
+const InstanceSize = 36
+
+// Instances accumulates glyph instances. Options.Indexed is ignored.
+type Instances struct {
+  Options
+  List []Instance
+}
+
+// NewInstances returns an empty Instances.
+func NewInstances(opts Options) *Instances {
+  return &Instances{Options: opts}
+}
+
+// Reset empties the list, keeping its memory.
+func (in *Instances) Reset() {
+  in.List = in.List[:0]
+}
+
+// unorm16 returns v, clamped to 0..1, as an unsigned normalized uint16.
+func unorm16(v float32) uint16 {
+  if v <= 0 {
+    return 0
+  }
+  if v >= 1 {
+    return 0xFFFF
+  }
+  return uint16(v*0xFFFF + 0.5)
+}
+
+// AddGlyph adds a glyph laid out in a box whose top left corner is at (x, y).
+func (in *Instances) AddGlyph(g layout.Glyph, x, y float32, c color.Color) {
+  if g.Item == nil || g.Item.Width == 0 || g.Item.Height == 0 {
+    return
+  }
+  q := g.Quad()
+  x0, y0 := in.point(x+q.X0, y+q.Y0)
+  x1, y1 := in.point(x+q.X1, y+q.Y1)
+  in.List = append(in.List, Instance{
+    X:     x0,
+    Y:     y0,
+    W:     x1 - x0,
+    H:     y1 - y0,
+    Scale: g.Scale,
+    U0:    unorm16(q.U0),
+    V0:    unorm16(q.V0),
+    U1:    unorm16(q.U1),
+    V1:    unorm16(q.V1),
+    Color: color.NRGBAModel.Convert(c).(color.NRGBA),
+    Page:  uint32(q.Page),
+  })
+}
+
+// AddText adds the glyphs of a layout result in color c, with the box's top
+// left corner at (x, y).
+func (in *Instances) AddText(r *layout.Result, x, y float32, c color.Color) {
+  for _, g := range r.Glyphs {
+    in.AddGlyph(g, x, y, c)
+  }
+}
+
+// AddSpans adds the glyphs of a result laid out by layout.LayoutSpans in the
+// colors of their spans, or c for spans without one.
+func (in *Instances) AddSpans(r *layout.Result, spans []layout.Span, x, y float32, c color.Color) {
+  for _, g := range r.Glyphs {
+    gc := c
+    if g.Span < len(spans) && spans[g.Span].Style.Color != nil {
+      gc = spans[g.Span].Style.Color
+    }
+    in.AddGlyph(g, x, y, gc)
+  }
+}
+
+// Bytes returns the instances little-endian, InstanceSize bytes each.
+func (in *Instances) Bytes() []byte {
+  b := make([]byte, len(in.List)*InstanceSize)
+  le := binary.LittleEndian
+  for k, i := range in.List {
+    p := b[k*InstanceSize:]
+    for n, v := range []float32{i.X, i.Y, i.W, i.H, i.Scale} {
+      le.PutUint32(p[4*n:], math.Float32bits(v))
+    }
+    for n, v := range []uint16{i.U0, i.V0, i.U1, i.V1} {
+      le.PutUint16(p[20+2*n:], v)
+    }
+    copy(p[28:], []byte{i.Color.R, i.Color.G, i.Color.B, i.Color.A})
+    le.PutUint32(p[32:], i.Page)
+  }
+  return b
+}
+
+// InstanceVertexShader is a GLSL 1.50 vertex shader drawing Instances as a
+// 4 vertex triangle strip, such as with
+// glDrawArraysInstanced(GL_TRIANGLE_STRIP, 0, 4, len(List)), with each
+// attribute's divisor set to 1:
+//
+//  glyphRect   vec4   glVertexAttribPointer(4, GL_FLOAT, false, 36, 0)
+//  glyphScale  float  glVertexAttribPointer(1, GL_FLOAT, false, 36, 16)
+//  glyphUV     vec4   glVertexAttribPointer(4, GL_UNSIGNED_SHORT, true, 36, 20)
+//  glyphColor  vec4   glVertexAttribPointer(4, GL_UNSIGNED_BYTE, true, 36, 28)
+//  glyphPage   uint   glVertexAttribIPointer(1, GL_UNSIGNED_INT, 36, 32)
+//
+// It expects instances in pixels with y growing down, as the zero Options
+// give, and moves them by the offset uniform before converting them with the
+// viewport uniform, the viewport size in pixels; scrolling text then only
+// changes a uniform. Go strings passed to GL need a trailing "\x00".
+const InstanceVertexShader = `#version 150
+
+in vec4 glyphRect;
+in float glyphScale;
+in vec4 glyphUV;
+in vec4 glyphColor;
+in uint glyphPage;
+uniform vec2 offset;
+uniform vec2 viewport;
+out vec2 uv;
+flat out uint page;
+out float scale;
+out vec4 color;
+
+void main() {
+  // strip corners (0,0) (1,0) (0,1) (1,1)
+  vec2 corner = vec2(gl_VertexID & 1, gl_VertexID >> 1);
+  uv = mix(glyphUV.xy, glyphUV.zw, corner);
+  page = glyphPage;
+  scale = glyphScale;
+  color = glyphColor;
+  vec2 position = (glyphRect.xy + corner*glyphRect.zw + offset) / viewport;
+  gl_Position = vec4(position.x*2.0 - 1.0, 1.0 - position.y*2.0, 0.0, 1.0);
+}
+`
+
+// InstanceFragmentShader is a GLSL 1.50 fragment shader for
+// InstanceVertexShader, sampling a signed distance field atlas whose pages
+// are the layers of a texture array.
+const InstanceFragmentShader = `#version 150
+
+in vec2 uv;
+flat in uint page;
+in float scale;
+in vec4 color;
+uniform sampler2DArray atlas;
+out vec4 outputColor;
+
+void main() {
+  float distance = texture(atlas, vec3(uv, float(page))).r;
+  float smoothing = 1.0/16.0/scale;
+  float alpha = smoothstep(0.5 - smoothing, 0.5 + smoothing, distance);
+  outputColor = vec4(color.rgb, color.a * alpha);
+}
+`
@@ -0,0 +1,70 @@
+package mesh
+
+import (
+  "encoding/binary"
+  "image/color"
+  "math"
+  "testing"
+)
+
+// TestInstanceBytes decodes Instances.Bytes in the layout InstanceVertexShader
+// reads and checks each instance against the glyph it was laid out from.
+func TestInstanceBytes(t *testing.T) {
+  _, r := testLayout(t, "Wave on")
+  glyphs := drawn(r)
+  le := binary.LittleEndian
+  float := func(b []byte) float32 {
+    return math.Float32frombits(le.Uint32(b))
+  }
+  unorm := func(b []byte) float32 {
+    return float32(le.Uint16(b)) / 0xFFFF
+  }
+  c := color.NRGBA{200, 100, 50, 128}
+  for _, opts := range []Options{{}, {Width: 800, Height: 600, YUp: true}} {
+    in := NewInstances(opts)
+    in.AddText(r, 10, 20, c)
+    if len(in.List) != len(glyphs) {
+      t.Fatalf("%d instances for %d glyphs", len(in.List), len(glyphs))
+    }
+    b := in.Bytes()
+    if len(b) != len(glyphs)*InstanceSize {
+      t.Fatalf("%d bytes for %d instances", len(b), len(glyphs))
+    }
+    pages := make(map[uint32]bool)
+    for k, g := range glyphs {
+      p := b[k*InstanceSize : (k+1)*InstanceSize]
+      q := g.Quad()
+      x0, y0, x1, y1 := 10+q.X0, 20+q.Y0, 10+q.X1, 20+q.Y1
+      if opts.YUp {
+        y0, y1 = 600-y0, 600-y1
+      }
+      if x, y, w, h := float(p), float(p[4:]), float(p[8:]), float(p[12:]); !near(x, x0) || !near(y, y0) || !near(w, x1-x0) || !near(h, y1-y0) {
+        t.Errorf("%+v: glyph %d at (%v, %v) size %vx%v, want (%v, %v) size %vx%v", opts, k, x, y, w, h, x0, y0, x1-x0, y1-y0)
+      }
+      if opts.YUp != (float(p[12:]) < 0) {
+        t.Errorf("%+v: glyph %d has height %v", opts, k, float(p[12:]))
+      }
+      if s := float(p[16:]); s != g.Scale {
+        t.Errorf("glyph %d has scale %v, want %v", k, s, g.Scale)
+      }
+      uv := [4]float32{unorm(p[20:]), unorm(p[22:]), unorm(p[24:]), unorm(p[26:])}
+      for n, want := range [4]float32{q.U0, q.V0, q.U1, q.V1} {
+        if math.Abs(float64(uv[n]-want)) > 1.0/0xFFFF {
+          t.Errorf("glyph %d has UVs %v, want %v", k, uv, [4]float32{q.U0, q.V0, q.U1, q.V1})
+          break
+        }
+      }
+      if got := (color.NRGBA{p[28], p[29], p[30], p[31]}); got != c {
+        t.Errorf("glyph %d has color %v, want %v", k, got, c)
+      }
+      page := le.Uint32(p[32:])
+      if int(page) != q.Page || int(page) != g.Item.ImageIndex {
+        t.Errorf("glyph %d on page %d, want %d", k, page, q.Page)
+      }
+      pages[page] = true
+    }
+    if len(pages) < 2 {
+      t.Errorf("instances on %d pages, want several", len(pages))
+    }
+  }
+}
@@ -0,0 +1,295 @@
+// Package mesh turns laid out glyphs into vertex data for any renderer:
+// interleaved vertices in a configurable format, as indexed quads or
+// triangle lists, in pixels or normalized device coordinates, with y growing
+// up or down.
+//
+// Glyphs are added in the coordinates of package layout, pixels with y
+// growing down from the top left of the viewport, and converted as they are
+// added according to Options.
+package mesh
+
+import (
+  "encoding/binary"
+  "image/color"
+  "math"
+
+  "github.com/vrav/ratlas/layout"
+)
+
+// Vertex is a corner of a glyph quad.
+type Vertex struct {
+  X, Y  float32
+  U, V  float32
+  Color color.NRGBA
+  Page  int
+
+  // Scale is the glyph's scale, which SDF shaders use to size their edge.
+  Scale float32
+}
+
+// Options configures the output coordinates and primitives of a Mesh.
+type Options struct {
+  // Width and Height are the size of the viewport in pixels, needed for
+  // NDC and for YUp.
+  Width, Height float32
+
+  // NDC outputs positions in normalized device coordinates, -1 to 1 across
+  // the viewport, instead of pixels.
+  NDC bool
+
+  // YUp outputs y growing upwards from the bottom of the viewport, as in
+  // OpenGL, instead of downwards from the top, as in Vulkan, Direct3D and
+  // image coordinates.
+  YUp bool
+
+  // Indexed outputs 4 vertices per quad and 6 indices into them, instead of
+  // 6 vertices making two triangles.
+  Indexed bool
+}
+
+// Mesh accumulates glyph quads. Triangles wind counter-clockwise as seen on
+// screen.
+type Mesh struct {
+  Options
+  Vertices []Vertex
+
+  // Indices holds the triangle indices of an indexed mesh.
+  Indices []uint32
+}
+
+// New returns an empty Mesh.
+func New(opts Options) *Mesh {
+  return &Mesh{Options: opts}
+}
+
+// Reset empties the mesh, keeping its memory.
+func (m *Mesh) Reset() {
+  m.Vertices = m.Vertices[:0]
+  m.Indices = m.Indices[:0]
+}
+
+// Quads returns the number of quads in the mesh.
+func (m *Mesh) Quads() int {
+  if m.Indexed {
+    return len(m.Vertices) / 4
+  }
+  return len(m.Vertices) / 6
+}
+
+// point converts a point from layout coordinates to output coordinates.
+func (o Options) point(x, y float32) (float32, float32) {
+  if o.YUp {
+    y = o.Height - y
+  }
+  if o.NDC {
+    x = 2*x/o.Width - 1
+    y = 2*y/o.Height - 1
+  }
+  return x, y
+}
+
+// AddQuad adds a quad offset by (x, y).
+func (m *Mesh) AddQuad(q layout.Quad, x, y, scale float32, c color.Color) {
+  nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+  corner := func(qx, qy, u, v float32) Vertex {
+    px, py := m.point(x+qx, y+qy)
+    return Vertex{X: px, Y: py, U: u, V: v, Color: nc, Page: q.Page, Scale: scale}
+  }
+  topLeft := corner(q.X0, q.Y0, q.U0, q.V0)
+  bottomLeft := corner(q.X0, q.Y1, q.U0, q.V1)
+  bottomRight := corner(q.X1, q.Y1, q.U1, q.V1)
+  topRight := corner(q.X1, q.Y0, q.U1, q.V0)
+
+  if m.Indexed {
+    base := uint32(len(m.Vertices))
+    m.Vertices = append(m.Vertices, topLeft, bottomLeft, bottomRight, topRight)
+    m.Indices = append(m.Indices, base, base+1, base+2, base, base+2, base+3)
+    return
+  }
+  m.Vertices = append(m.Vertices, topLeft, bottomLeft, bottomRight, topLeft, bottomRight, topRight)
+}
+
+// AddGlyph adds the quad of a glyph laid out in a box whose top left corner
+// is at (x, y).
+func (m *Mesh) AddGlyph(g layout.Glyph, x, y float32, c color.Color) {
+  if g.Item == nil || g.Item.Width == 0 || g.Item.Height == 0 {
+    return
+  }
+  m.AddQuad(g.Quad(), x, y, g.Scale, c)
+}
+
+// AddText adds the glyphs of a layout result in color c, with the box's top
+// left corner at (x, y).
+func (m *Mesh) AddText(r *layout.Result, x, y float32, c color.Color) {
+  for _, g := range r.Glyphs {
+    m.AddGlyph(g, x, y, c)
+  }
+}
+
+// AddSpans adds the glyphs of a result laid out by layout.LayoutSpans in the
+// colors of their spans, or c for spans without one.
+func (m *Mesh) AddSpans(r *layout.Result, spans []layout.Span, x, y float32, c color.Color) {
+  for _, g := range r.Glyphs {
+    gc := c
+    if g.Span < len(spans) && spans[g.Span].Style.Color != nil {
+      gc = spans[g.Span].Style.Color
+    }
+    m.AddGlyph(g, x, y, gc)
+  }
+}
+
+// Attribute is a vertex attribute.
+type Attribute int
+
+const (
+  // Position is x and y as float32s.
+  Position Attribute = iota
+
+  // TexCoord is u and v, float32s or, packed, unsigned normalized uint16s,
+  // which hold only normalized UVs: pack atlases with UVUnits of
+  // ratlas.UVPixels into float32s.
+  TexCoord
+
+  // Color is r, g, b and a with straight alpha, float32s from 0 to 1 or,
+  // packed, uint8s.
+  Color
+
+  // Page is the atlas image index, a float32 or, packed, a uint32.
+  Page
+
+  // Scale is the glyph's scale as a float32.
+  Scale
+)
+
+// Format is the layout of an interleaved vertex: its attributes in order,
+// each tightly packed after the last.
+type Format struct {
+  Attributes []Attribute
+
+  // Packed stores texture coordinates, colors and pages as integers, to
+  // halve the size of vertices. Texture coordinates are clamped to 0..1, so
+  // UVs in pixels don't survive it.
+  Packed bool
+}
+
+// Size returns the size of an attribute in bytes.
+func (f Format) Size(a Attribute) int {
+  switch a {
+  case Position:
+    return 8
+  case TexCoord:
+    if f.Packed {
+      return 4
+    }
+    return 8
+  case Color:
+    if f.Packed {
+      return 4
+    }
+    return 16
+  }
+  return 4
+}
+
+// Stride returns the size of a vertex in bytes.
+func (f Format) Stride() int {
+  stride := 0
+  for _, a := range f.Attributes {
+    stride += f.Size(a)
+  }
+  return stride
+}
+
+// Offset returns the byte offset of an attribute in a vertex, or -1 if the
+// format doesn't have it.
+func (f Format) Offset(a Attribute) int {
+  offset := 0
+  for _, b := range f.Attributes {
+    if b == a {
+      return offset
+    }
+    offset += f.Size(b)
+  }
+  return -1
+}
+
+// Floats returns the vertices as interleaved float32s with the given
+// attributes, as an unpacked Format would store them.
+func (m *Mesh) Floats(attributes ...Attribute) []float32 {
+  var floats []float32
+  for _, v := range m.Vertices {
+    for _, a := range attributes {
+      switch a {
+      case Position:
+        floats = append(floats, v.X, v.Y)
+      case TexCoord:
+        floats = append(floats, v.U, v.V)
+      case Color:
+        floats = append(floats, float32(v.Color.R)/255, float32(v.Color.G)/255, float32(v.Color.B)/255, float32(v.Color.A)/255)
+      case Page:
+        floats = append(floats, float32(v.Page))
+      case Scale:
+        floats = append(floats, v.Scale)
+      }
+    }
+  }
+  return floats
+}
+
+// Bytes returns the vertices in format f, little-endian.
+func (m *Mesh) Bytes(f Format) []byte {
+  b := make([]byte, 0, len(m.Vertices)*f.Stride())
+  var scratch [4]byte
+  put16 := func(v uint16) {
+    binary.LittleEndian.PutUint16(scratch[:], v)
+    b = append(b, scratch[:2]...)
+  }
+  put32 := func(v uint32) {
+    binary.LittleEndian.PutUint32(scratch[:], v)
+    b = append(b, scratch[:]...)
+  }
+  putFloat := func(v float32) {
+    put32(math.Float32bits(v))
+  }
+  for _, v := range m.Vertices {
+    for _, a := range f.Attributes {
+      switch {
+      case a == Position:
+        putFloat(v.X)
+        putFloat(v.Y)
+      case a == TexCoord && f.Packed:
+        put16(unorm16(v.U))
+        put16(unorm16(v.V))
+      case a == TexCoord:
+        putFloat(v.U)
+        putFloat(v.V)
+      case a == Color && f.Packed:
+        b = append(b, v.Color.R, v.Color.G, v.Color.B, v.Color.A)
+      case a == Color:
+        for _, c := range []uint8{v.Color.R, v.Color.G, v.Color.B, v.Color.A} {
+          putFloat(float32(c) / 255)
+        }
+      case a == Page && f.Packed:
+        put32(uint32(v.Page))
+      case a == Page:
+        putFloat(float32(v.Page))
+      case a == Scale:
+        putFloat(v.Scale)
+      }
+    }
+  }
+  return b
+}
+
+// Indices16 returns the indices as uint16s, or false if the mesh has too
+// many vertices for them.
+func (m *Mesh) Indices16() ([]uint16, bool) {
+  if len(m.Vertices) > 1<<16 {
+    return nil, false
+  }
+  indices := make([]uint16, len(m.Indices))
+  for k, i := range m.Indices {
+    indices[k] = uint16(i)
+  }
+  return indices, true
+}
@@ -0,0 +1,214 @@
+package mesh
+
+import (
+  "encoding/binary"
+  "image/color"
+  "io/ioutil"
+  "math"
+  "testing"
+
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/layout"
+)
+
+// testLayout lays out text from an atlas of small pages, so glyphs land on
+// several of them.
+func testLayout(t *testing.T, text string) (*ratlas.Atlas, *layout.Result) {
+  data, err := ioutil.ReadFile("../example/Vera.ttf")
+  if err != nil {
+    t.Fatal(err)
+  }
+  atlas := ratlas.NewWithOptions(&data, []rune(text), ratlas.Options{FontPt: 32, Width: 48, Height: 48, Pad: 2})
+  if len(atlas.Images) < 2 {
+    t.Fatalf("%d pages, want several", len(atlas.Images))
+  }
+  return &atlas, layout.Layout(&atlas, text, layout.Options{Size: 24})
+}
+
+// drawn returns the glyphs of r that have an image.
+func drawn(r *layout.Result) []layout.Glyph {
+  var glyphs []layout.Glyph
+  for _, g := range r.Glyphs {
+    if g.Item != nil && g.Item.Width > 0 && g.Item.Height > 0 {
+      glyphs = append(glyphs, g)
+    }
+  }
+  return glyphs
+}
+
+func near(a, b float32) bool {
+  return math.Abs(float64(a-b)) < 1e-4
+}
+
+func TestFormat(t *testing.T) {
+  all := []Attribute{Position, TexCoord, Color, Page, Scale}
+  for _, test := range []struct {
+    format  Format
+    stride  int
+    offsets []int
+  }{
+    {Format{Attributes: all}, 40, []int{0, 8, 16, 32, 36}},
+    {Format{Attributes: all, Packed: true}, 24, []int{0, 8, 12, 16, 20}},
+    {Format{Attributes: []Attribute{TexCoord, Position}}, 16, []int{8, 0, -1, -1, -1}},
+  } {
+    if got := test.format.Stride(); got != test.stride {
+      t.Errorf("%+v: stride %d, want %d", test.format, got, test.stride)
+    }
+    for i, a := range all {
+      if got := test.format.Offset(a); got != test.offsets[i] {
+        t.Errorf("%+v: attribute %d at %d, want %d", test.format, a, got, test.offsets[i])
+      }
+    }
+  }
+}
+
+func TestBytes(t *testing.T) {
+  _, r := testLayout(t, "Wave")
+  m := New(Options{})
+  m.AddText(r, 5, 7, color.NRGBA{10, 20, 30, 40})
+  all := []Attribute{Position, TexCoord, Color, Page, Scale}
+  le := binary.LittleEndian
+  float := func(b []byte) float32 {
+    return math.Float32frombits(le.Uint32(b))
+  }
+
+  f := Format{Attributes: all}
+  b := m.Bytes(f)
+  if len(b) != len(m.Vertices)*f.Stride() {
+    t.Fatalf("%d bytes for %d vertices", len(b), len(m.Vertices))
+  }
+  floats := m.Floats(all...)
+  for i := range floats {
+    if got := float(b[4*i:]); got != floats[i] {
+      t.Fatalf("float %d is %v in Bytes, %v in Floats", i, got, floats[i])
+    }
+  }
+  for i, v := range m.Vertices {
+    p := b[i*f.Stride():]
+    c := p[f.Offset(Color):]
+    if float(p) != v.X || float(p[4:]) != v.Y || float(p[f.Offset(TexCoord):]) != v.U || float(p[f.Offset(TexCoord)+4:]) != v.V ||
+      float(c) != 10.0/255 || float(c[12:]) != 40.0/255 || float(p[f.Offset(Page):]) != float32(v.Page) || float(p[f.Offset(Scale):]) != v.Scale {
+      t.Errorf("vertex %d: % x, want %+v", i, p[:f.Stride()], v)
+    }
+  }
+
+  f.Packed = true
+  b = m.Bytes(f)
+  if len(b) != len(m.Vertices)*f.Stride() {
+    t.Fatalf("%d packed bytes for %d vertices", len(b), len(m.Vertices))
+  }
+  pages := make(map[int]bool)
+  for i, v := range m.Vertices {
+    p := b[i*f.Stride():]
+    u, w := le.Uint16(p[f.Offset(TexCoord):]), le.Uint16(p[f.Offset(TexCoord)+2:])
+    c := p[f.Offset(Color):]
+    if float(p) != v.X || float(p[4:]) != v.Y || math.Abs(float64(u)/0xFFFF-float64(v.U)) > 1.0/0xFFFF || math.Abs(float64(w)/0xFFFF-float64(v.V)) > 1.0/0xFFFF ||
+      c[0] != 10 || c[1] != 20 || c[2] != 30 || c[3] != 40 || int(le.Uint32(p[f.Offset(Page):])) != v.Page || float(p[f.Offset(Scale):]) != v.Scale {
+      t.Errorf("packed vertex %d: % x, want %+v", i, p[:f.Stride()], v)
+    }
+    pages[v.Page] = true
+  }
+  if len(pages) < 2 {
+    t.Errorf("vertices on %d pages, want several", len(pages))
+  }
+}
+
+func TestIndexed(t *testing.T) {
+  _, r := testLayout(t, "Wave on")
+  glyphs := drawn(r)
+  triangles := New(Options{})
+  triangles.AddText(r, 0, 0, color.White)
+  indexed := New(Options{Indexed: true})
+  indexed.AddText(r, 0, 0, color.White)
+
+  if triangles.Quads() != len(glyphs) || indexed.Quads() != len(glyphs) {
+    t.Fatalf("%d and %d quads for %d glyphs", triangles.Quads(), indexed.Quads(), len(glyphs))
+  }
+  if len(triangles.Vertices) != 6*len(glyphs) || len(triangles.Indices) != 0 {
+    t.Errorf("triangle list of %d vertices, %d indices", len(triangles.Vertices), len(triangles.Indices))
+  }
+  if len(indexed.Vertices) != 4*len(glyphs) || len(indexed.Indices) != 6*len(glyphs) {
+    t.Errorf("indexed quads of %d vertices, %d indices", len(indexed.Vertices), len(indexed.Indices))
+  }
+  // the indices pick the triangle list's vertices
+  for k, i := range indexed.Indices {
+    if indexed.Vertices[i] != triangles.Vertices[k] {
+      t.Errorf("index %d is vertex %+v, want %+v", k, indexed.Vertices[i], triangles.Vertices[k])
+    }
+  }
+  indices, ok := indexed.Indices16()
+  if !ok || len(indices) != len(indexed.Indices) {
+    t.Fatalf("%d 16 bit indices, %v", len(indices), ok)
+  }
+  for k, i := range indices {
+    if uint32(i) != indexed.Indices[k] {
+      t.Errorf("16 bit index %d is %d, not %d", k, i, indexed.Indices[k])
+    }
+  }
+
+  // triangles wind counter-clockwise on screen, with y down
+  for k := 0; k+2 < len(triangles.Vertices); k += 3 {
+    a, b, c := triangles.Vertices[k], triangles.Vertices[k+1], triangles.Vertices[k+2]
+    if cross := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X); cross >= 0 {
+      t.Errorf("triangle %d winds clockwise", k/3)
+    }
+  }
+
+  triangles.Reset()
+  if len(triangles.Vertices) != 0 || triangles.Quads() != 0 {
+    t.Errorf("%d vertices after Reset", len(triangles.Vertices))
+  }
+}
+
+func TestCoordinates(t *testing.T) {
+  _, r := testLayout(t, "Wave")
+  const w, h = 800, 600
+  pixels := New(Options{Width: w, Height: h})
+  pixels.AddText(r, 10, 20, color.White)
+  for _, test := range []struct {
+    opts Options
+    x, y func(x, y float32) float32
+  }{
+    {Options{YUp: true}, func(x, y float32) float32 { return x }, func(x, y float32) float32 { return h - y }},
+    {Options{NDC: true}, func(x, y float32) float32 { return 2*x/w - 1 }, func(x, y float32) float32 { return 2*y/h - 1 }},
+    {Options{NDC: true, YUp: true}, func(x, y float32) float32 { return 2*x/w - 1 }, func(x, y float32) float32 { return 1 - 2*y/h }},
+  } {
+    test.opts.Width, test.opts.Height = w, h
+    m := New(test.opts)
+    m.AddText(r, 10, 20, color.White)
+    for i, v := range m.Vertices {
+      p := pixels.Vertices[i]
+      if x, y := test.x(p.X, p.Y), test.y(p.X, p.Y); !near(v.X, x) || !near(v.Y, y) {
+        t.Errorf("%+v: vertex %d at (%v, %v), want (%v, %v)", test.opts, i, v.X, v.Y, x, y)
+      }
+      if v.U != p.U || v.V != p.V {
+        t.Errorf("%+v: vertex %d UVs moved", test.opts, i)
+      }
+    }
+  }
+}
+
+func TestUVs(t *testing.T) {
+  atlas, r := testLayout(t, "Wave")
+  for _, origin := range []ratlas.UVOrigin{ratlas.UVTopLeft, ratlas.UVBottomLeft} {
+    if err := atlas.SetUVConvention(origin, ratlas.UVNormalized); err != nil {
+      t.Fatal(err)
+    }
+    m := New(Options{Indexed: true})
+    m.AddText(r, 0, 0, color.White)
+    for k, g := range drawn(r) {
+      item := g.Item
+      q := m.Vertices[4*k : 4*k+4]
+      // top left, bottom left, bottom right, top right
+      want := [4][2]float32{{item.U0, item.V0}, {item.U0, item.V1}, {item.U1, item.V1}, {item.U1, item.V0}}
+      for c, v := range q {
+        if v.U != want[c][0] || v.V != want[c][1] || v.Page != item.ImageIndex {
+          t.Errorf("origin %d: glyph %d corner %d has UV (%v, %v) on page %d, want %v on page %d", origin, k, c, v.U, v.V, v.Page, want[c], item.ImageIndex)
+        }
+      }
+      if q[0].Y >= q[1].Y || q[0].X >= q[3].X {
+        t.Errorf("origin %d: glyph %d corners out of order: %+v", origin, k, q)
+      }
+    }
+  }
+}
@@ -0,0 +1,126 @@
+package ratlas
+
+import (
+  "image"
+  "image/draw"
+)
+
+// mipAlign returns the texels glyphs are aligned to for mip chains of levels
+// levels.
+func mipAlign(levels int) int {
+  if levels < 2 {
+    return 1
+  }
+  return 1 << uint(levels-1)
+}
+
+func alignUp(v, align int) int {
+  return (v + align - 1) / align * align
+}
+
+// MipPad returns the padding around glyphs at a mip level, in texels of that
+// level: Pad halves at each level, and distance fields need some of it left
+// at the last level for their edges to fade out. A Pad of at least
+// 2^(MipLevels-1) keeps a texel of it down to the last level.
+func (atlas *Atlas) MipPad(level int) int {
+  return atlas.Pad >> uint(level)
+}
+
+// MipChain returns a page and its mip levels, down to MipLevels levels, or
+// to 1x1 if the atlas wasn't packed for mipmaps. Each glyph is downsampled
+// on its own, averaging 2x2 texels of the level above without reaching past
+// its packed rectangle, so glyphs keep their edges in place and don't bleed
+// into each other.
+func (atlas *Atlas) MipChain(page int) []draw.Image {
+  img := atlas.Images[page]
+  var chain []draw.Image
+  for _, p := range atlas.mipChain(page, pagePlane(img, 4)) {
+    chain = append(chain, p.image(img))
+  }
+  return chain
+}
+
+// mipChain returns the mip chain of p, the texels of a page.
+func (atlas *Atlas) mipChain(page int, p *plane) []*plane {
+  if atlas.MipLevels == 0 {
+    return p.mipChain()
+  }
+  rects := atlas.glyphRects(page)
+  chain := []*plane{p}
+  for level := 1; level < atlas.MipLevels && (p.w > 1 || p.h > 1); level++ {
+    q := p.half()
+    for k, r := range rects {
+      rects[k] = q.halfWithin(p, r)
+    }
+    chain = append(chain, q)
+    p = q
+  }
+  return chain
+}
+
+// glyphRects returns the packed rectangles of the glyphs on a page, or on the
+// glyph page an effect page belongs to.
+func (atlas *Atlas) glyphRects(page int) []image.Rectangle {
+  page %= atlas.glyphPages()
+  align := mipAlign(atlas.MipLevels)
+  var rects []image.Rectangle
+  for _, atlasItem := range atlas.allItems() {
+    if atlasItem.Node == nil || atlasItem.ImageIndex != page {
+      continue
+    }
+    x, y := atlasItem.Node.X, atlasItem.Node.Y
+    rects = append(rects, image.Rect(x, y, x+alignUp(atlasItem.Width, align), y+alignUp(atlasItem.Height, align)))
+  }
+  return rects
+}
+
+// halfWithin downsamples the texels of p in r into q, the next level, taking
+// no texels from outside r, and returns the rectangle written.
+func (q *plane) halfWithin(p *plane, r image.Rectangle) image.Rectangle {
+  r = r.Intersect(image.Rect(0, 0, p.w, p.h))
+  dst := image.Rect(r.Min.X/2, r.Min.Y/2, (r.Max.X+1)/2, (r.Max.Y+1)/2).Intersect(image.Rect(0, 0, q.w, q.h))
+  at := func(x, y, c int) uint32 {
+    if x >= r.Max.X {
+      x = r.Max.X - 1
+    }
+    if y >= r.Max.Y {
+      y = r.Max.Y - 1
+    }
+    return uint32(p.pix[(y*p.w+x)*p.n+c])
+  }
+  for y := dst.Min.Y; y < dst.Max.Y; y++ {
+    for x := dst.Min.X; x < dst.Max.X; x++ {
+      // odd rectangles start mid block
+      sx, sy := 2*x, 2*y
+      if sx < r.Min.X {
+        sx = r.Min.X
+      }
+      if sy < r.Min.Y {
+        sy = r.Min.Y
+      }
+      for c := 0; c < p.n; c++ {
+        sum := at(sx, sy, c) + at(sx+1, sy, c) + at(sx, sy+1, c) + at(sx+1, sy+1, c)
+        q.pix[(y*q.w+x)*q.n+c] = uint16((sum + 2) / 4)
+      }
+    }
+  }
+  return dst
+}
+
+// image returns p as a page of the same type as like, from the channels
+// pagePlane returns.
+func (p *plane) image(like draw.Image) draw.Image {
+  img := newPageLike(like, image.Rect(0, 0, p.w, p.h))
+  if nrgba, ok := img.(*image.NRGBA); ok {
+    for i, v := range p.pix {
+      nrgba.Pix[i] = uint8(v >> 8)
+    }
+    return nrgba
+  }
+  for y := 0; y < p.h; y++ {
+    for x := 0; x < p.w; x++ {
+      setCoverage(img, x, y, p.pix[(y*p.w+x)*p.n])
+    }
+  }
+  return img
+}
@@ -0,0 +1,75 @@
+package ratlas
+
+import (
+  "image"
+  "testing"
+)
+
+// TestMipAlign checks that glyphs are packed at multiples of
+// 2^(MipLevels-1), in rectangles rounded up to it that don't overlap, so
+// each glyph keeps texels of its own down to the last level.
+func TestMipAlign(t *testing.T) {
+  data := testFont(t)
+  for levels := 0; levels <= 4; levels++ {
+    atlas := NewWithOptions(&data, []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ.,|"), Options{FontPt: 24, Width: 128, Height: 128, Pad: 1, MipLevels: levels})
+    align := mipAlign(levels)
+    if levels >= 2 && align != 1<<uint(levels-1) || levels < 2 && align != 1 {
+      t.Errorf("%d levels: alignment %d", levels, align)
+    }
+    for page := range atlas.Images {
+      rects := atlas.glyphRects(page)
+      for i, r := range rects {
+        if r.Min.X%align != 0 || r.Min.Y%align != 0 || r.Dx()%align != 0 || r.Dy()%align != 0 {
+          t.Errorf("%d levels: glyph at %v isn't aligned to %d", levels, r, align)
+        }
+        for _, s := range rects[i+1:] {
+          if r.Overlaps(s) {
+            t.Errorf("%d levels: glyphs at %v and %v overlap", levels, r, s)
+          }
+        }
+      }
+    }
+  }
+}
+
+// TestMipBleed fills each glyph's packed rectangle with a value of its own,
+// and the gutters between them with another, and checks that each glyph's
+// rectangle still holds only its value at every mip level.
+func TestMipBleed(t *testing.T) {
+  atlas := textureTestAtlas(t, false)
+  for page, img := range atlas.Images {
+    rects := atlas.glyphRects(page)
+    p := pagePlane(img, 1)
+    for i := range p.pix {
+      p.pix[i] = 0xFFFF
+    }
+    for k, r := range rects {
+      for y := r.Min.Y; y < r.Max.Y; y++ {
+        for x := r.Min.X; x < r.Max.X; x++ {
+          p.pix[y*p.w+x] = uint16(1000 * (k + 1))
+        }
+      }
+    }
+
+    chain := atlas.mipChain(page, p)
+    if len(chain) != atlas.MipLevels {
+      t.Fatalf("page %d: %d levels, want %d", page, len(chain), atlas.MipLevels)
+    }
+    for level, q := range chain {
+      for k, r := range rects {
+        // aligned, the rectangle halves exactly
+        r = image.Rect(r.Min.X>>uint(level), r.Min.Y>>uint(level), r.Max.X>>uint(level), r.Max.Y>>uint(level))
+        if r.Empty() {
+          t.Errorf("page %d level %d: glyph %d has no texels", page, level, k)
+        }
+        for y := r.Min.Y; y < r.Max.Y; y++ {
+          for x := r.Min.X; x < r.Max.X; x++ {
+            if v := q.pix[y*q.w+x]; v != uint16(1000*(k+1)) {
+              t.Fatalf("page %d level %d: texel (%d, %d) of glyph %d is %d, want %d", page, level, x, y, k, v, 1000*(k+1))
+            }
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,204 @@
+package ratlas
+
+import (
+  "fmt"
+  "image"
+  "image/draw"
+
+  "github.com/golang/freetype/truetype"
+  "golang.org/x/image/font"
+  "golang.org/x/image/math/fixed"
+  "golang.org/x/image/vector"
+)
+
+// loadGlyph loads the outline of a glyph index at the atlas font size, DPI and hinting.
+func (atlas *Atlas) loadGlyph(index truetype.Index) (*truetype.GlyphBuf, error) {
+  if atlas.Font == nil {
+    return nil, fmt.Errorf("ratlas: no font loaded")
+  }
+  var glyphBuf truetype.GlyphBuf
+  err := glyphBuf.Load(atlas.Font, fixed.Int26_6(atlas.PixelsPerEm()*64), index, atlas.FaceOptions.Hinting)
+  if err != nil {
+    return nil, fmt.Errorf("ratlas: couldn't load glyph %d: %v", index, err)
+  }
+  atlas.synthesize(&glyphBuf)
+  return &glyphBuf, nil
+}
+
+// synthetic reports whether glyphs are synthetically slanted or emboldened.
+func (atlas *Atlas) synthetic() bool {
+  return atlas.Oblique != 0 || atlas.Embolden != 0
+}
+
+// synthesize shears a loaded glyph by Oblique and makes room for Embolden,
+// moving it right by half of it and growing its bounds and advance. The
+// emboldening strokes themselves are added by drawGlyph.
+func (atlas *Atlas) synthesize(glyphBuf *truetype.GlyphBuf) {
+  if !atlas.synthetic() {
+    return
+  }
+  half := fixed.Int26_6(atlas.Embolden * 32)
+  for i := range glyphBuf.Points {
+    p := &glyphBuf.Points[i]
+    p.X += fixed.Int26_6(float32(p.Y)*atlas.Oblique) + half
+  }
+  
+  // shearing moves the control box, so find it again
+  if len(glyphBuf.Points) > 0 {
+    glyphBuf.Bounds.Min.X, glyphBuf.Bounds.Max.X = glyphBuf.Points[0].X, glyphBuf.Points[0].X
+    for _, p := range glyphBuf.Points {
+      if p.X < glyphBuf.Bounds.Min.X {
+        glyphBuf.Bounds.Min.X = p.X
+      }
+      if p.X > glyphBuf.Bounds.Max.X {
+        glyphBuf.Bounds.Max.X = p.X
+      }
+    }
+    if atlas.FaceOptions.Hinting != font.HintingNone {
+      // rounded out to whole pixels, as hinted glyphs load
+      glyphBuf.Bounds.Min.X &^= 63
+      glyphBuf.Bounds.Max.X = (glyphBuf.Bounds.Max.X + 63) &^ 63
+    }
+    glyphBuf.Bounds.Min.X -= half
+    glyphBuf.Bounds.Max.X += half
+    glyphBuf.Bounds.Min.Y -= half
+    glyphBuf.Bounds.Max.Y += half
+  }
+  glyphBuf.AdvanceWidth += 2 * half
+}
+
+// glyphBounds mirrors font.Face.GlyphBounds for a glyph index, with Y growing downwards.
+func (atlas *Atlas) glyphBounds(index truetype.Index) (fixed.Rectangle26_6, fixed.Int26_6, error) {
+  glyphBuf, err := atlas.loadGlyph(index)
+  if err != nil {
+    return fixed.Rectangle26_6{}, 0, err
+  }
+  bounds := fixed.Rectangle26_6{
+    Min: fixed.Point26_6{X: glyphBuf.Bounds.Min.X, Y: -glyphBuf.Bounds.Max.Y},
+    Max: fixed.Point26_6{X: glyphBuf.Bounds.Max.X, Y: -glyphBuf.Bounds.Min.Y},
+  }
+  return bounds, glyphBuf.AdvanceWidth, nil
+}
+
+// glyphOrigin returns where a glyph's origin is in its atlas image, padded
+// by pad and moved right by a subpixel phase.
+func glyphOrigin(glyphBuf *truetype.GlyphBuf, pad int, phase float32) (float32, float32) {
+  minX := (glyphBuf.Bounds.Min.X + fixed.Int26_6(phase*64)).Floor()
+  minY := (-glyphBuf.Bounds.Max.Y).Floor()
+  return float32(pad-minX) + phase, float32(pad - minY)
+}
+
+// drawGlyph renders a glyph index in white onto dst, offset by pad as in New
+// and moved right by a subpixel phase.
+func (atlas *Atlas) drawGlyph(dst draw.Image, index truetype.Index, pad int, phase float32) error {
+  glyphBuf, err := atlas.loadGlyph(index)
+  if err != nil {
+    return err
+  }
+  dx, dy := glyphOrigin(glyphBuf, pad, phase)
+  
+  size := dst.Bounds().Size()
+  r := vector.NewRasterizer(size.X, size.Y)
+  e0 := 0
+  for _, e1 := range glyphBuf.Ends {
+    drawContour(r, glyphBuf.Points[e0:e1], dx, dy)
+    e0 = e1
+  }
+  r.Draw(dst, dst.Bounds(), image.White, image.Point{})
+  if atlas.dilation() > 0 {
+    // dilate for Embolden and stem darkening, stroking edges into a mask of
+    // their own so strokes in counters don't cancel the glyph's own winding
+    bold := image.NewAlpha(dst.Bounds())
+    atlas.strokeGlyph(glyphBuf, bold, dx, dy, 1, 1, atlas.dilation(), JoinRound)
+    draw.DrawMask(dst, dst.Bounds(), image.White, image.Point{}, bold, bold.Bounds().Min, draw.Over)
+  }
+  return nil
+}
+
+// pather receives paths, as a vector.Rasterizer does.
+type pather interface {
+  MoveTo(x, y float32)
+  LineTo(x, y float32)
+  QuadTo(bx, by, cx, cy float32)
+  ClosePath()
+}
+
+// scaledPather passes paths on to p scaled by (sx, sy).
+type scaledPather struct {
+  p pather
+  sx, sy float32
+}
+
+func (s scaledPather) MoveTo(x, y float32) {
+  s.p.MoveTo(x*s.sx, y*s.sy)
+}
+
+func (s scaledPather) LineTo(x, y float32) {
+  s.p.LineTo(x*s.sx, y*s.sy)
+}
+
+func (s scaledPather) QuadTo(bx, by, cx, cy float32) {
+  s.p.QuadTo(bx*s.sx, by*s.sy, cx*s.sx, cy*s.sy)
+}
+
+func (s scaledPather) ClosePath() {
+  s.p.ClosePath()
+}
+
+// drawContour adds a closed quadratic TrueType contour to the rasterizer.
+// Points are in 26.6 with Y growing upwards; output is in pixels with Y growing downwards.
+func drawContour(r pather, ps []truetype.Point, dx, dy float32) {
+  if len(ps) == 0 {
+    return
+  }
+  pt := func(p truetype.Point) (float32, float32) {
+    return dx + fixedFloat(p.X), dy - fixedFloat(p.Y)
+  }
+  onCurve := func(p truetype.Point) bool {
+    return p.Flags&0x01 != 0
+  }
+  
+  // find a starting on-curve point, synthesizing one between two off-curve points if needed
+  var startX, startY float32
+  others := ps
+  if onCurve(ps[0]) {
+    startX, startY = pt(ps[0])
+    others = ps[1:]
+  } else if last := ps[len(ps)-1]; onCurve(last) {
+    startX, startY = pt(last)
+    others = ps[:len(ps)-1]
+  } else {
+    x0, y0 := pt(ps[0])
+    x1, y1 := pt(last)
+    startX, startY = (x0+x1)/2, (y0+y1)/2
+  }
+  
+  r.MoveTo(startX, startY)
+  var qx, qy float32
+  pending := false
+  for _, p := range others {
+    x, y := pt(p)
+    if onCurve(p) {
+      if pending {
+        r.QuadTo(qx, qy, x, y)
+      } else {
+        r.LineTo(x, y)
+      }
+      pending = false
+      continue
+    }
+    if pending {
+      // two off-curve points imply an on-curve point half way between them
+      mx, my := (qx+x)/2, (qy+y)/2
+      r.QuadTo(qx, qy, mx, my)
+    }
+    qx, qy = x, y
+    pending = true
+  }
+  if pending {
+    r.QuadTo(qx, qy, startX, startY)
+  } else {
+    r.LineTo(startX, startY)
+  }
+  r.ClosePath()
+}
@@ -0,0 +1,94 @@
+package ratlas
+
+import (
+  "math"
+  "testing"
+)
+
+// rowInk returns the coverage of each row of an item, and the coverage
+// weighted x of each row's ink, in texels of the item.
+func rowInk(t *testing.T, atlas *Atlas, item *AtlasItem) (sums, centers []float64) {
+  values := layerValues(t, atlas, item, EffectFill)
+  w := item.Width
+  for y := 0; y < item.Height; y++ {
+    var sum, x float64
+    for k, v := range values[y*w : (y+1)*w] {
+      sum += float64(v)
+      x += float64(v) * (float64(k) + 0.5)
+    }
+    sums = append(sums, sum)
+    centers = append(centers, x/math.Max(sum, 1e-9))
+  }
+  return sums, centers
+}
+
+// TestEmbolden checks that Embolden widens stems, bounds and advances by
+// its width.
+func TestEmbolden(t *testing.T) {
+  data := testFont(t)
+  opts := Options{FontPt: 48, Width: 256, Height: 256, Pad: 2}
+  plain := NewWithOptions(&data, []rune("Il"), opts)
+  for _, embolden := range []float32{1, 2, 3} {
+    opts.Embolden = embolden
+    bold := NewWithOptions(&data, []rune("Il"), opts)
+    for _, r := range "Il" {
+      b, p := bold.Items[r], plain.Items[r]
+      if b.Advance != p.Advance+embolden {
+        t.Errorf("embolden %v: %q advances %v, want %v", embolden, r, b.Advance, p.Advance+embolden)
+      }
+      if d := b.Width - p.Width; float32(d) < embolden-1 || float32(d) > embolden+1 {
+        t.Errorf("embolden %v: %q is %d texels wide, %d without", embolden, r, b.Width, p.Width)
+      }
+      if d := b.Height - p.Height; float32(d) < embolden-1 || float32(d) > embolden+1 {
+        t.Errorf("embolden %v: %q is %d texels high, %d without", embolden, r, b.Height, p.Height)
+      }
+
+      // the stem, halfway up
+      bs, _ := rowInk(t, &bold, b)
+      ps, _ := rowInk(t, &plain, p)
+      stem, plainStem := bs[len(bs)/2], ps[len(ps)/2]
+      if math.Abs(stem-plainStem-float64(embolden)) > 0.25 {
+        t.Errorf("embolden %v: %q has a stem %.2f wide, %.2f without", embolden, r, stem, plainStem)
+      }
+    }
+  }
+}
+
+// TestOblique checks that Oblique shears glyphs right by its slant per pixel
+// above the baseline, without changing advances.
+func TestOblique(t *testing.T) {
+  data := testFont(t)
+  opts := Options{FontPt: 48, Width: 256, Height: 256, Pad: 2}
+  for _, oblique := range []float32{0, 0.2, -0.1, 0.35} {
+    opts.Oblique = oblique
+    atlas := NewWithOptions(&data, []rune("I"), opts)
+    plain := NewWithOptions(&data, []rune("I"), Options{FontPt: 48, Width: 256, Height: 256, Pad: 2})
+    item, p := atlas.Items['I'], plain.Items['I']
+    if item.Advance != p.Advance || item.Height != p.Height {
+      t.Errorf("oblique %v: advance %v height %d, want %v and %d", oblique, item.Advance, item.Height, p.Advance, p.Height)
+    }
+    // the stem's top and bottom rows, inside its serifless ends
+    sums, centers := rowInk(t, &atlas, item)
+    top, bottom := -1, -1
+    for y, s := range sums {
+      if s > 1 {
+        if top < 0 {
+          top = y
+        }
+        bottom = y
+      }
+    }
+    top, bottom = top+2, bottom-2
+    slant := (centers[top] - centers[bottom]) / float64(bottom-top)
+    if math.Abs(slant-float64(oblique)) > 0.02 {
+      t.Errorf("oblique %v: stem slants by %.3f", oblique, slant)
+    }
+    // the stem keeps its width across rows
+    if math.Abs(sums[top]-sums[bottom]) > 0.25 {
+      t.Errorf("oblique %v: stem is %.2f wide at the top, %.2f at the bottom", oblique, sums[top], sums[bottom])
+    }
+    if grown := float64(item.Width - p.Width); math.Abs(grown-math.Abs(float64(oblique))*float64(p.Height-2*opts.Pad)) > 2 {
+      t.Errorf("oblique %v: %d texels wide, %d unslanted", oblique, item.Width, p.Width)
+    }
+  }
+}
@@ -0,0 +1,135 @@
+package ratlas
+
+import (
+  "encoding/binary"
+  "fmt"
+  "image"
+  "image/draw"
+)
+
+// TextureArray returns the pages as the layers of a texture array, for
+// binding every page at once: their pixels one layer after another, tightly
+// packed, with 16 bit values little-endian. An item's ImageIndex is its
+// layer. Pages must all be the same size and type.
+func (atlas *Atlas) TextureArray() (pix []byte, width, height, layers int, err error) {
+  if len(atlas.Images) == 0 {
+    return nil, 0, 0, 0, fmt.Errorf("ratlas: no pages")
+  }
+  size := atlas.Images[0].Bounds().Size()
+  for i, img := range atlas.Images {
+    if img.Bounds().Size() != size || fmt.Sprintf("%T", img) != fmt.Sprintf("%T", atlas.Images[0]) {
+      return nil, 0, 0, 0, fmt.Errorf("ratlas: page %d is a %v %T, not a %v %T", i, img.Bounds().Size(), img, size, atlas.Images[0])
+    }
+  }
+  for _, img := range atlas.Images {
+    b := img.Bounds()
+    switch img := img.(type) {
+    case *image.Gray:
+      for y := b.Min.Y; y < b.Max.Y; y++ {
+        pix = append(pix, img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]...)
+      }
+    case *image.Alpha:
+      for y := b.Min.Y; y < b.Max.Y; y++ {
+        pix = append(pix, img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]...)
+      }
+    case *image.RGBA:
+      for y := b.Min.Y; y < b.Max.Y; y++ {
+        pix = append(pix, img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]...)
+      }
+    case *image.NRGBA:
+      for y := b.Min.Y; y < b.Max.Y; y++ {
+        pix = append(pix, img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]...)
+      }
+    case *image.Gray16:
+      var v [2]byte
+      for y := b.Min.Y; y < b.Max.Y; y++ {
+        for x := b.Min.X; x < b.Max.X; x++ {
+          binary.LittleEndian.PutUint16(v[:], img.Gray16At(x, y).Y)
+          pix = append(pix, v[:]...)
+        }
+      }
+    default:
+      return nil, 0, 0, 0, fmt.Errorf("ratlas: can't lay out %T pages as a texture array", img)
+    }
+  }
+  return pix, size.X, size.Y, len(atlas.Images), nil
+}
+
+// MergePages packs pages into sheets of cols by rows pages, such as into a
+// single sheet to draw from one texture, updating the ImageIndex, Node and
+// Percent fields of items to match. Effect pages are merged alike, so
+// EffectImage still finds them. Pages must all be the same size.
+func (atlas *Atlas) MergePages(cols, rows int) error {
+  if len(atlas.Images) == 0 || cols < 1 || rows < 1 {
+    return fmt.Errorf("ratlas: nothing to merge into %d by %d pages", cols, rows)
+  }
+  size := atlas.Images[0].Bounds().Size()
+  for i, img := range atlas.Images {
+    if img.Bounds().Size() != size {
+      return fmt.Errorf("ratlas: page %d is %v, not %v", i, img.Bounds().Size(), size)
+    }
+  }
+
+  // glyph pages and each set of effect pages are merged separately
+  perSheet := cols * rows
+  pages := atlas.glyphPages()
+  blocks := len(atlas.Images) / pages
+  sheets := (pages + perSheet - 1) / perSheet
+  sheetRect := image.Rect(0, 0, cols*size.X, rows*size.Y)
+
+  var merged []draw.Image
+  for block := 0; block < blocks; block++ {
+    for sheet := 0; sheet < sheets; sheet++ {
+      dst := newPageLike(atlas.Images[block*pages], sheetRect)
+      for slot := 0; slot < perSheet; slot++ {
+        i := sheet*perSheet + slot
+        if i >= pages {
+          break
+        }
+        src := atlas.Images[block*pages+i]
+        at := image.Pt(slot%cols*size.X, slot/cols*size.Y)
+        draw.Draw(dst, image.Rectangle{at, at.Add(size)}, src, src.Bounds().Min, draw.Src)
+      }
+      merged = append(merged, dst)
+    }
+  }
+
+  for _, atlasItem := range atlas.allItems() {
+    if atlasItem.Node == nil {
+      continue
+    }
+    slot := atlasItem.ImageIndex % perSheet
+    atlasItem.ImageIndex /= perSheet
+    atlasItem.Node.X += slot % cols * size.X
+    atlasItem.Node.Y += slot / cols * size.Y
+    atlas.setPercents(atlasItem, sheetRect.Dx(), sheetRect.Dy())
+  }
+  atlas.Images = merged
+  fmt.Println("ratlas: merged", pages*blocks, "pages into", len(merged))
+  return nil
+}
+
+// glyphPages returns the number of pages holding glyphs, which effect pages
+// follow as many at a time.
+func (atlas *Atlas) glyphPages() int {
+  layers := atlas.Effects.layers()
+  if atlas.Effects.Storage == EffectChannels {
+    return len(atlas.Images)
+  }
+  return len(atlas.Images) / (len(layers) + 1)
+}
+
+// newPageLike returns an empty page of the same type as img.
+func newPageLike(img draw.Image, r image.Rectangle) draw.Image {
+  switch img.(type) {
+  case *image.Gray:
+    return FormatGray.newPage(r)
+  case *image.Alpha:
+    return image.NewAlpha(r)
+  case *image.Gray16:
+    return image.NewGray16(r)
+  case *image.NRGBA:
+    return image.NewNRGBA(r)
+  }
+  return image.NewRGBA(r)
+}
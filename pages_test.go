@@ -0,0 +1,163 @@
+package ratlas
+
+import (
+  "encoding/binary"
+  "image"
+  "image/color"
+  "testing"
+)
+
+// rectColors returns the colors of the texels of img in r.
+func rectColors(img image.Image, r image.Rectangle) []color.Color {
+  var out []color.Color
+  for y := r.Min.Y; y < r.Max.Y; y++ {
+    for x := r.Min.X; x < r.Max.X; x++ {
+      out = append(out, img.At(x, y))
+    }
+  }
+  return out
+}
+
+func sameTexels(a, b []color.Color) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+  return true
+}
+
+// TestMergePages merges atlases of several pages, with and without effect
+// pages, into one sheet and into several, and checks that each item finds
+// the texels it had on its original page.
+func TestMergePages(t *testing.T) {
+  data := testFont(t)
+  const w, h = 64, 64
+  for _, effects := range []Effects{{}, {OutlineWidth: 2, Storage: EffectPages}} {
+    for _, grid := range [][2]int{{8, 1}, {2, 2}, {1, 2}} {
+      atlas := NewWithOptions(&data, []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"), Options{FontPt: 24, Width: w, Height: h, Pad: 2, Effects: effects})
+      pages := atlas.glyphPages()
+      if pages < 3 || pages > 8 {
+        t.Fatalf("%d pages, want 3 to 8", pages)
+      }
+      layers := []Effect{EffectFill}
+      if effects.OutlineWidth > 0 {
+        layers = append(layers, EffectOutline)
+      }
+      type original struct {
+        page   int
+        node   image.Point
+        texels [][]color.Color
+      }
+      originals := make(map[rune]original)
+      for r, item := range atlas.Items {
+        o := original{item.ImageIndex, image.Pt(item.Node.X, item.Node.Y), nil}
+        for _, layer := range layers {
+          img, _, ok := atlas.EffectImage(item, layer)
+          if !ok {
+            t.Fatalf("no layer %d", layer)
+          }
+          o.texels = append(o.texels, rectColors(img, itemRect(item)))
+        }
+        originals[r] = o
+      }
+
+      cols, rows := grid[0], grid[1]
+      if err := atlas.MergePages(cols, rows); err != nil {
+        t.Fatal(err)
+      }
+      perSheet := cols * rows
+      sheets := (pages + perSheet - 1) / perSheet
+      if len(atlas.Images) != sheets*len(layers) {
+        t.Fatalf("%d by %d: %d pages, want %d", cols, rows, len(atlas.Images), sheets*len(layers))
+      }
+      sw, sh := cols*w, rows*h
+      for i, img := range atlas.Images {
+        if img.Bounds() != image.Rect(0, 0, sw, sh) {
+          t.Errorf("%d by %d: sheet %d is %v", cols, rows, i, img.Bounds())
+        }
+      }
+      for r, item := range atlas.Items {
+        o := originals[r]
+        slot := o.page % perSheet
+        at := image.Pt(slot%cols*w, slot/cols*h).Add(o.node)
+        if item.ImageIndex != o.page/perSheet || item.Node.X != at.X || item.Node.Y != at.Y {
+          t.Errorf("%d by %d: %q on sheet %d at (%d, %d), want %d at %v", cols, rows, r, item.ImageIndex, item.Node.X, item.Node.Y, o.page/perSheet, at)
+        }
+        if item.PercentPosX*float32(sw) != float32(at.X) || item.PercentPosY*float32(sh) != float32(at.Y) ||
+          item.PercentWidth*float32(sw) != float32(item.Width) || item.PercentHeight*float32(sh) != float32(item.Height) {
+          t.Errorf("%d by %d: %q has Percent fields (%v, %v) %vx%v on its sheet", cols, rows, r, item.PercentPosX, item.PercentPosY, item.PercentWidth, item.PercentHeight)
+        }
+        if item.U0 != item.PercentPosX || item.V1 != item.PercentPosY+item.PercentHeight {
+          t.Errorf("%d by %d: %q has UVs (%v, %v)-(%v, %v) off its Percent fields", cols, rows, r, item.U0, item.V0, item.U1, item.V1)
+        }
+        for k, layer := range layers {
+          img, _, ok := atlas.EffectImage(item, layer)
+          if !ok || !sameTexels(rectColors(img, itemRect(item)), o.texels[k]) {
+            t.Errorf("%d by %d: %q has other texels in layer %d", cols, rows, r, layer)
+          }
+        }
+      }
+    }
+  }
+}
+
+// TestTextureArray lays out pages of each format as array layers, in page
+// order, and checks that pages must match.
+func TestTextureArray(t *testing.T) {
+  data := testFont(t)
+  const w, h = 128, 64
+  for _, format := range []PixelFormat{FormatGray, FormatAlpha, FormatRGBA, FormatGray16} {
+    atlas := NewWithOptions(&data, []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"), Options{FontPt: 24, Width: w, Height: h, Pad: 2, Format: format})
+    pix, width, height, layers, err := atlas.TextureArray()
+    if err != nil {
+      t.Fatal(err)
+    }
+    if width != w || height != h || layers != len(atlas.Images) || layers < 2 {
+      t.Fatalf("format %d: %d layers of %dx%d, want %d of %dx%d", format, layers, width, height, len(atlas.Images), w, h)
+    }
+    size := len(pix) / layers
+    if size*layers != len(pix) || size%(w*h) != 0 {
+      t.Fatalf("format %d: %d bytes for %d layers", format, len(pix), layers)
+    }
+    for i, img := range atlas.Images {
+      layer := pix[i*size : (i+1)*size]
+      var want []byte
+      switch img := img.(type) {
+      case *image.Gray:
+        want = img.Pix
+      case *image.Alpha:
+        want = img.Pix
+      case *image.RGBA:
+        want = img.Pix
+      case *image.Gray16:
+        // little-endian, where images are big-endian
+        for k := 0; k < len(img.Pix); k += 2 {
+          want = append(want, img.Pix[k+1], img.Pix[k])
+        }
+        if v := img.Gray16At(3, 5).Y; binary.LittleEndian.Uint16(layer[2*(5*w+3):]) != v {
+          t.Errorf("format %d: texel (3, 5) of layer %d isn't %d", format, i, v)
+        }
+      default:
+        t.Fatalf("format %d: %T pages", format, img)
+      }
+      if string(layer) != string(want) {
+        t.Errorf("format %d: layer %d isn't page %d", format, i, i)
+      }
+    }
+  }
+
+  // pages of one size and type only
+  atlas := textureTestAtlas(t, false)
+  atlas.Images[1] = image.NewGray(image.Rect(0, 0, 64, 64))
+  if _, _, _, _, err := atlas.TextureArray(); err == nil {
+    t.Errorf("no error for pages of two sizes")
+  }
+  atlas.Images[1] = image.NewAlpha(atlas.Images[0].Bounds())
+  if _, _, _, _, err := atlas.TextureArray(); err == nil {
+    t.Errorf("no error for pages of two types")
+  }
+}
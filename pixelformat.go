@@ -0,0 +1,106 @@
+package ratlas
+
+import (
+  "image"
+  "image/draw"
+)
+
+// PixelFormat is the pixel format of atlas pages holding coverage. Pages of
+// LCD atlases and of effects stored in channels are always *image.NRGBA.
+type PixelFormat int
+
+const (
+  // FormatGray is coverage as *image.Gray, white on black, read from the
+  // red channel.
+  FormatGray PixelFormat = iota
+  
+  // FormatAlpha is coverage as *image.Alpha, for blending by alpha.
+  FormatAlpha
+  
+  // FormatRGBA is premultiplied white with coverage in alpha as
+  // *image.RGBA, drawn with ordinary premultiplied blending and no custom
+  // shader.
+  FormatRGBA
+  
+  // FormatGray16 is coverage as *image.Gray16, rasterized at 16 bits. It
+  // holds coverage, not distances; it only gives a distance field generated
+  // from its pages more levels to start from.
+  FormatGray16
+)
+
+// newPage returns an empty page of the format.
+func (format PixelFormat) newPage(r image.Rectangle) draw.Image {
+  switch format {
+  case FormatAlpha:
+    return image.NewAlpha(r)
+  case FormatRGBA:
+    return image.NewRGBA(r)
+  case FormatGray16:
+    return image.NewGray16(r)
+  }
+  page := image.NewGray(r)
+  draw.Draw(page, r, image.Black, image.Point{}, draw.Src)
+  return page
+}
+
+// convert returns an image loaded from a file in the format. PNG has no
+// alpha-only or premultiplied images, so those pages load as NRGBA.
+func (format PixelFormat) convert(img draw.Image) draw.Image {
+  b := img.Bounds()
+  var dst draw.Image
+  switch format {
+  case FormatAlpha:
+    if _, ok := img.(*image.Alpha); ok {
+      return img
+    }
+    dst = image.NewAlpha(b)
+  case FormatRGBA:
+    if _, ok := img.(*image.RGBA); ok {
+      return img
+    }
+    dst = image.NewRGBA(b)
+  case FormatGray16:
+    if _, ok := img.(*image.Gray16); ok {
+      return img
+    }
+    dst = image.NewGray16(b)
+  default:
+    return img
+  }
+  draw.Draw(dst, b, img, b.Min, draw.Src)
+  return dst
+}
+
+// copyCoverage copies coverage from src to rect of a page.
+func copyCoverage(page draw.Image, rect image.Rectangle, src *image.Gray) {
+  switch page := page.(type) {
+  case *image.Alpha:
+    for y := 0; y < rect.Dy(); y++ {
+      copy(page.Pix[page.PixOffset(rect.Min.X, rect.Min.Y+y):], src.Pix[y*src.Stride:y*src.Stride+rect.Dx()])
+    }
+  case *image.RGBA:
+    for y := 0; y < rect.Dy(); y++ {
+      for x := 0; x < rect.Dx(); x++ {
+        v := src.Pix[y*src.Stride+x]
+        i := page.PixOffset(rect.Min.X+x, rect.Min.Y+y)
+        page.Pix[i], page.Pix[i+1], page.Pix[i+2], page.Pix[i+3] = v, v, v, v
+      }
+    }
+  default:
+    draw.Draw(page, rect, src, image.Point{}, draw.Src)
+  }
+}
+
+// coveragePage reports whether page i of total pages holds coverage in the
+// atlas' PixelFormat rather than NRGBA channels.
+func (atlas *Atlas) coveragePage(i, total int) bool {
+  layers := atlas.Effects.layers()
+  switch {
+  case atlas.LCD.Order != LCDNone:
+    // effect pages follow the LCD pages
+    return len(layers) > 0 && i >= total/(len(layers)+1)
+  case len(layers) > 0 && atlas.Effects.Storage == EffectChannels:
+    return false
+  }
+  return true
+}
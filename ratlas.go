@@ -8,8 +8,10 @@ import (
   "bytes"
   "sort"
   "fmt"
+  "io"
   "os"
   "io/ioutil"
+  "math"
   
   "image"
   "image/draw"
@@ -25,8 +27,10 @@ func fixedFloat(v fixed.Int26_6) float32 {
 }
 
 // AtlasItem contains all the information needed to draw a specific rune within an Atlas.
+// Items added by glyph index alone (see Options.Glyphs) have a Rune of -1.
 type AtlasItem struct {
   Rune rune
+  Glyph truetype.Index
   Advance float32
   BearingX float32
   Descent float32
@@ -34,19 +38,168 @@ type AtlasItem struct {
   PercentPosY float32
   PercentWidth float32
   PercentHeight float32
+  
+  // U0, V0 and U1, V1 are the UVs of the item's top left and bottom right
+  // corners in the atlas' UVOrigin and UVUnits.
+  U0, V0, U1, V1 float32
+  
   Width int
   Height int
   Node *node
   ImageIndex int
+  
+  // Variants holds the glyph rasterized at subpixel phases 0, 1/N, ...,
+  // (N-1)/N pixels, if the atlas was built with Options.SubpixelVariants.
+  // See Variant.
+  Variants []*AtlasItem
+  
+  // UVInset is how far in texels the Percent fields lie inside the item's
+  // texels on each side, 0.5 for UVHalfTexelInset; Glyph quads shrink by it
+  // to keep texels and pixels aligned.
+  UVInset float32
+  
+  // phase is the subpixel phase of a variant being baked.
+  phase float32
 }
 
 type Atlas struct {
   Face font.Face
+  Font *truetype.Font
   FontPt float64
   Pad int
   
   Items map[rune]*AtlasItem
+  Glyphs map[truetype.Index]*AtlasItem
   Images []draw.Image
+  
+  // Effects are the glyph effects baked into Images.
+  Effects Effects
+  
+  // Embolden and Oblique are the synthetic styles of Options the glyphs
+  // were baked with. Item metrics include them; advances from a shaper,
+  // which reads the font itself, don't include Embolden.
+  Embolden, Oblique float32
+  
+  // FaceOptions is how ReloadFont rasterizes the font, as the atlas was built.
+  FaceOptions FaceOptions
+  
+  // LCD is the subpixel layout Images were baked for, if any.
+  LCD LCD
+  
+  // Coverage is the adjustment of coverage values Images were baked with.
+  Coverage Coverage
+  
+  // Format is the pixel format of Images, which LoadImageFiles converts to.
+  Format PixelFormat
+  
+  // MipLevels is the number of mip levels glyphs were packed for, which
+  // MipChain and texture files with mipmaps stop at.
+  MipLevels int
+  
+  // Spacing and Border are the empty texels packed between glyph images and
+  // around page edges, and UVMode how item Percent fields were set.
+  Spacing, Border int
+  UVMode UVMode
+  
+  // UVOrigin and UVUnits are the convention of item UVs; see
+  // SetUVConvention.
+  UVOrigin UVOrigin
+  UVUnits UVUnits
+}
+
+// FaceOptions configures the rasterization of an atlas font. Zero fields
+// take the defaults ratlas has always used: 72 DPI, no hinting, 512 glyph
+// cache entries and 4 by 1 sub-pixel positions.
+type FaceOptions struct {
+  // DPI is the resolution FontPt is rendered at; item metrics, images and
+  // layout results are in pixels at this resolution.
+  DPI float64
+  
+  Hinting font.Hinting
+  GlyphCacheEntries int
+  SubPixelsX, SubPixelsY int
+}
+
+// withDefaults returns the options with zero fields set to their defaults.
+func (opts FaceOptions) withDefaults() FaceOptions {
+  if opts.DPI == 0 {
+    opts.DPI = 72
+  }
+  if opts.GlyphCacheEntries == 0 {
+    opts.GlyphCacheEntries = 512
+  }
+  if opts.SubPixelsX == 0 {
+    opts.SubPixelsX = 4
+  }
+  if opts.SubPixelsY == 0 {
+    opts.SubPixelsY = 1
+  }
+  return opts
+}
+
+// Options configures atlas creation with NewWithOptions.
+type Options struct {
+  FontPt float64
+  Width, Height int
+  
+  // Pad is the margin around each glyph within its image, such as for the
+  // spread of distance fields; it's part of item metrics.
+  Pad int
+  
+  // Spacing is the gutter of empty texels between packed glyph images, and
+  // Border that around page edges, so that sampling one glyph never reaches
+  // another or wraps around; neither is part of item metrics.
+  Spacing, Border int
+  
+  // UVMode sets whether item Percent fields span the outer edges of glyph
+  // texels or the centers of their edge texels.
+  UVMode UVMode
+  
+  // UVOrigin and UVUnits set the convention of item UVs, top left origin and
+  // normalized by default.
+  UVOrigin UVOrigin
+  UVUnits UVUnits
+  
+  // Glyphs lists additional glyph indices to bake, such as ligatures and
+  // contextual forms reachable only through a shaper's substitutions.
+  Glyphs []truetype.Index
+  
+  // Effects bakes outlines, shadows and glows around glyphs.
+  Effects Effects
+  
+  // Embolden synthesizes bold for fonts without it, dilating glyph outlines
+  // so stems grow by this many pixels, and widening advances to match.
+  Embolden float32
+  
+  // Oblique synthesizes italics for fonts without them, shearing glyph
+  // outlines right by this much per pixel above the baseline; 0.2 slants
+  // them by about 11 degrees.
+  Oblique float32
+  
+  // FaceOptions sets the DPI, hinting, glyph cache and sub-pixel positions of
+  // the font face, such as full hinting for small UI text.
+  FaceOptions FaceOptions
+  
+  // SubpixelVariants bakes each glyph at this many horizontal subpixel
+  // phases, such as 4 for 0, 1/4, 1/2 and 3/4 pixels, so small text drawn at
+  // fractional positions stays crisp; see AtlasItem.Variant.
+  SubpixelVariants int
+  
+  // LCD bakes subpixel antialiased glyphs for LCD screens of an order, on
+  // RGBA pages. Effects are then stored on pages of their own.
+  LCD LCD
+  
+  // Coverage sets gamma, contrast and stem darkening of baked coverage.
+  Coverage Coverage
+  
+  // Format is the pixel format of pages, image.Gray by default.
+  Format PixelFormat
+  
+  // MipLevels packs glyphs for mip chains of this many levels, starting on
+  // and spanning multiples of 2^(MipLevels-1) texels, so that downsampling
+  // never mixes neighboring glyphs; see Atlas.MipPad for the padding left at
+  // each level. Page sizes should be multiples of it too.
+  MipLevels int
 }
 
 // atlasItems implements Sort interface for slice of AtlasItem
@@ -71,30 +224,111 @@ type node struct {
   X, Y, W, H int
 }
 func (atlas Atlas) containsNilNodes() bool {
-  for _, atlasItem := range atlas.Items {
+  return len(atlas.getNilNodes()) > 0
+}
+func (atlas Atlas) getNilNodes() atlasItems {
+  var itemSlice atlasItems
+  for _, atlasItem := range atlas.allItems() {
     if atlasItem.Node == nil {
-      return true
+      itemSlice = append(itemSlice, atlasItem)
     }
   }
-  return false
+  return itemSlice
 }
-func (atlas Atlas) getNilNodes() atlasItems {
+// allItems returns every item of the atlas, whether reachable by rune or by glyph index alone, and their variants.
+func (atlas Atlas) allItems() atlasItems {
+  itemSlice := append(atlas.itemsByRune(), atlas.glyphOnlyItems()...)
+  var variants atlasItems
+  for _, atlasItem := range itemSlice {
+    for _, variant := range atlasItem.Variants {
+      // phase 0 is missing until packing is done
+      if variant != nil {
+        variants = append(variants, variant)
+      }
+    }
+  }
+  return append(itemSlice, variants...)
+}
+// glyphOnlyItems returns items that are reachable by glyph index but not by rune.
+func (atlas Atlas) glyphOnlyItems() atlasItems {
   var itemSlice atlasItems
-  for _, atlasItem := range atlas.Items {
-    if atlasItem.Node == nil {
+  for _, atlasItem := range atlas.Glyphs {
+    if atlasItem.Rune < 0 {
       itemSlice = append(itemSlice, atlasItem)
     }
   }
   return itemSlice
 }
-func fitAtlasItems(items atlasItems, w, h int) {
-  root := &node{ X: 0, Y: 0, W: w, H: h}
+// fitAtlasItems packs items spacing texels apart and border texels from page
+// edges, with sizes rounded up to multiples of align, so they start on
+// multiples of it too.
+func fitAtlasItems(items atlasItems, w, h, align, spacing, border int) {
+  root := packRoot(w, h, align, spacing, border)
   for _, item := range items {
-    if node := root.findNode(item.Width, item.Height); node != nil {
-      item.Node = node.splitNode(item.Width, item.Height)
+    iw, ih := packSize(item, align, spacing)
+    if node := root.findNode(iw, ih); node != nil {
+      item.Node = node.splitNode(iw, ih)
+    }
+  }
+}
+// packRoot returns the free space of an empty page for fitAtlasItems.
+func packRoot(w, h, align, spacing, border int) *node {
+  // trailing spacing may overlap the far border
+  border = alignUp(border, align)
+  return &node{ X: border, Y: border, W: w - 2*border + spacing, H: h - 2*border + spacing}
+}
+// packSize returns the space fitAtlasItems packs an item in.
+func packSize(item *AtlasItem, align, spacing int) (int, int) {
+  return alignUp(item.Width+spacing, align), alignUp(item.Height+spacing, align)
+}
+// dropOversized removes items, and variants, too large for even an empty page
+// to hold, so packing doesn't add pages for them forever.
+func (atlas *Atlas) dropOversized(w, h, align, spacing, border int) {
+  root := packRoot(w, h, align, spacing, border)
+  fits := func(item *AtlasItem) bool {
+    iw, ih := packSize(item, align, spacing)
+    return iw <= root.W && ih <= root.H
+  }
+  for _, atlasItem := range append(atlas.itemsByRune(), atlas.glyphOnlyItems()...) {
+    if !fits(atlasItem) {
+      fmt.Printf("ratlas: skipping glyph %d, %dx%d doesn't fit on a %dx%d page\n", atlasItem.Glyph, atlasItem.Width, atlasItem.Height, w, h)
+      atlas.dropItem(atlasItem)
+      continue
+    }
+    for _, variant := range atlasItem.Variants {
+      if variant != nil && !fits(variant) {
+        fmt.Println("ratlas: skipping subpixel variants of glyph", atlasItem.Glyph)
+        atlasItem.Variants = nil
+        break
+      }
+    }
+  }
+}
+// dropItem removes an item from the atlas, or a variant with its siblings.
+func (atlas *Atlas) dropItem(atlasItem *AtlasItem) {
+  if atlasItem.Rune >= 0 && atlas.Items[atlasItem.Rune] == atlasItem {
+    delete(atlas.Items, atlasItem.Rune)
+  }
+  if atlas.Glyphs[atlasItem.Glyph] == atlasItem {
+    delete(atlas.Glyphs, atlasItem.Glyph)
+  }
+  for _, item := range append(atlas.itemsByRune(), atlas.glyphOnlyItems()...) {
+    for _, variant := range item.Variants {
+      if variant == atlasItem {
+        item.Variants = nil
+        break
+      }
     }
   }
 }
+// itemsByRune returns the items of Items.
+func (atlas Atlas) itemsByRune() atlasItems {
+  var itemSlice atlasItems
+  for _, atlasItem := range atlas.Items {
+    itemSlice = append(itemSlice, atlasItem)
+  }
+  return itemSlice
+}
 func (root *node) findNode(w, h int) *node {
   if (root.Used) {
     rightFind := root.Right.findNode(w, h)
@@ -131,6 +365,46 @@ func (atlas *Atlas) GobEncode() ([]byte, error) {
     if err != nil {
         return nil, err
     }
+    err = encoder.Encode(atlas.glyphOnlyItems())
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode(atlas.Effects)
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode([]float32{atlas.Embolden, atlas.Oblique})
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode(atlas.FaceOptions)
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode(atlas.LCD)
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode(atlas.Coverage)
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode(atlas.Format)
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode(atlas.MipLevels)
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode([]int{atlas.Spacing, atlas.Border, int(atlas.UVMode)})
+    if err != nil {
+        return nil, err
+    }
+    err = encoder.Encode([]int{int(atlas.UVOrigin), int(atlas.UVUnits)})
+    if err != nil {
+        return nil, err
+    }
     return w.Bytes(), nil
 }
 func (atlas *Atlas) GobDecode(buf []byte) error {
@@ -144,7 +418,87 @@ func (atlas *Atlas) GobDecode(buf []byte) error {
     if err!=nil {
         return err
     }
-    return decoder.Decode(&atlas.Items)
+    err = decoder.Decode(&atlas.Items)
+    if err!=nil {
+        return err
+    }
+    
+    // values below were added after the original format; older gobs end early
+    var glyphItems atlasItems
+    err = decoder.Decode(&glyphItems)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    err = decoder.Decode(&atlas.Effects)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    var synthetic []float32
+    err = decoder.Decode(&synthetic)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    if len(synthetic) == 2 {
+      atlas.Embolden, atlas.Oblique = synthetic[0], synthetic[1]
+    }
+    err = decoder.Decode(&atlas.FaceOptions)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    err = decoder.Decode(&atlas.LCD)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    err = decoder.Decode(&atlas.Coverage)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    err = decoder.Decode(&atlas.Format)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    err = decoder.Decode(&atlas.MipLevels)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    var packing []int
+    err = decoder.Decode(&packing)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    if len(packing) == 3 {
+      atlas.Spacing, atlas.Border, atlas.UVMode = packing[0], packing[1], UVMode(packing[2])
+    }
+    var uvs []int
+    err = decoder.Decode(&uvs)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    if len(uvs) == 2 {
+      atlas.UVOrigin, atlas.UVUnits = UVOrigin(uvs[0]), UVUnits(uvs[1])
+    }
+    atlas.indexGlyphs()
+    for _, atlasItem := range glyphItems {
+      atlas.Glyphs[atlasItem.Glyph] = atlasItem
+    }
+    if atlas.UVUnits == UVNormalized {
+      // older gobs have no UVs, and normalized ones don't need pages
+      atlas.SetUVConvention(atlas.UVOrigin, atlas.UVUnits)
+    }
+    return nil
+}
+
+// indexGlyphs rebuilds the Glyphs map from the glyph indices of Items.
+// Items without a known glyph index (index 0, e.g. from older gobs) are skipped.
+func (atlas *Atlas) indexGlyphs() {
+  if atlas.Glyphs == nil {
+    atlas.Glyphs = make(map[truetype.Index]*AtlasItem)
+  }
+  for _, atlasItem := range atlas.Items {
+    if atlasItem.Glyph != 0 {
+      atlas.Glyphs[atlasItem.Glyph] = atlasItem
+    }
+  }
 }
 
 func (atlas *Atlas) createGob() ([]byte, error) {
@@ -214,7 +568,7 @@ func (atlas *Atlas) SaveImageFiles(name string) error {
     defer outFile.Close()
     err = png.Encode(outFile, img)
     if err != nil {
-      return fmt.Errorf("ratlas: couldn't encode png:", err)
+      return fmt.Errorf("ratlas: couldn't encode png: %v", err)
     }
     fmt.Println("ratlas: wrote", outFilename)
   }
@@ -222,7 +576,9 @@ func (atlas *Atlas) SaveImageFiles(name string) error {
 }
 
 // LoadImageFiles loads a slice of strings that point to image files to load into the atlas.
+// Images are converted to the atlas' Format, so load its gob first.
 func (atlas *Atlas) LoadImageFiles(imageFilenames []string) error {
+  total := len(atlas.Images) + len(imageFilenames)
   for _, imageFilename := range imageFilenames {
     inFile, err := os.Open(imageFilename)
     if err != nil {
@@ -240,13 +596,16 @@ func (atlas *Atlas) LoadImageFiles(imageFilenames []string) error {
       return fmt.Errorf("ratlas: couldn't create drawable image from %s\n", imageFilename)
     }
     
+    if atlas.coveragePage(len(atlas.Images), total) {
+      dimg = atlas.Format.convert(dimg)
+    }
     atlas.Images = append(atlas.Images, dimg)
     fmt.Printf("ratlas: loaded %s as %s\n", imageFilename, formatString)
   }
   return nil
 }
 
-// ReloadFont parses TTF data in order to generate a font.Face for the atlas.
+// ReloadFont parses TTF data in order to generate a font.Face for the atlas, configured by its FaceOptions.
 func (atlas *Atlas) ReloadFont(ttfData *[]byte) error {
   // parse file bytes into font
   f, err := truetype.Parse(*ttfData)
@@ -254,28 +613,64 @@ func (atlas *Atlas) ReloadFont(ttfData *[]byte) error {
     return fmt.Errorf("ratlas: couldn't parse font: %v", err)
   }
   
+  faceOpts := atlas.FaceOptions.withDefaults()
   opts := &truetype.Options{
     Size: atlas.FontPt,
-    DPI: 72.0,
-    Hinting: font.HintingNone,
-    GlyphCacheEntries: 512,
-    SubPixelsX: 4,
-    SubPixelsY: 1,
+    DPI: faceOpts.DPI,
+    Hinting: faceOpts.Hinting,
+    GlyphCacheEntries: faceOpts.GlyphCacheEntries,
+    SubPixelsX: faceOpts.SubPixelsX,
+    SubPixelsY: faceOpts.SubPixelsY,
   }
   face := truetype.NewFace(f, opts)
   fmt.Println("ratlas: loaded and parsed TTF data")
   
   atlas.Face = face
+  atlas.Font = f
+  
+  // items loaded from older gobs don't know their glyph index yet
+  for _, atlasItem := range atlas.Items {
+    if atlasItem.Glyph == 0 {
+      atlasItem.Glyph = f.Index(atlasItem.Rune)
+    }
+  }
+  atlas.indexGlyphs()
   
   return nil
 }
 
+// PixelsPerEm returns the font size in pixels, FontPt at the atlas DPI, as shapers expect it.
+func (atlas *Atlas) PixelsPerEm() float32 {
+  return float32(atlas.FontPt * atlas.FaceOptions.withDefaults().DPI / 72)
+}
+
+// Glyph returns the AtlasItem for a glyph index, as produced by a shaper.
+func (atlas *Atlas) Glyph(index truetype.Index) (*AtlasItem, bool) {
+  atlasItem, ok := atlas.Glyphs[index]
+  return atlasItem, ok
+}
+
 // ScaleNumbers scales the numbers within an Atlas and its AtlasItem(s), for example, if a loaded image was scaled since saving the atlas info.
 func (atlas *Atlas) ScaleNumbers(v float32) {
   atlas.FontPt *= float64(v)
   atlas.Pad = int(float32(atlas.Pad)*v)
+  atlas.Spacing = int(float32(atlas.Spacing)*v)
+  atlas.Border = int(float32(atlas.Border)*v)
+  atlas.Effects.OutlineWidth *= v
+  atlas.Effects.ShadowX *= v
+  atlas.Effects.ShadowY *= v
+  atlas.Effects.ShadowBlur *= v
+  atlas.Effects.GlowRadius *= v
+  atlas.Embolden *= v
   
-  for _, atlasItem := range atlas.Items {
+  for _, atlasItem := range atlas.allItems() {
+    // the scaled page size, for UVs in pixels; Percent fields don't change
+    var size image.Point
+    if atlasItem.PercentWidth > 0 && atlasItem.PercentHeight > 0 {
+      size.X = int((float32(atlasItem.Width)-2*atlasItem.UVInset)/atlasItem.PercentWidth*v + 0.5)
+      size.Y = int((float32(atlasItem.Height)-2*atlasItem.UVInset)/atlasItem.PercentHeight*v + 0.5)
+    }
+    
     atlasItem.Advance *= v
     atlasItem.BearingX *= v
     atlasItem.Descent *= v
@@ -286,6 +681,7 @@ func (atlas *Atlas) ScaleNumbers(v float32) {
     atlasItem.Node.Y = int(float32(atlasItem.Node.Y)*v)
     atlasItem.Node.W = atlasItem.Width
     atlasItem.Node.H = atlasItem.Height
+    atlasItem.U0, atlasItem.V0, atlasItem.U1, atlasItem.V1 = uvRect(atlasItem, atlas.UVOrigin, atlas.UVUnits, size)
   }
   fmt.Println("ratlas: scaled atlas numbers by", v)
 }
@@ -315,12 +711,71 @@ func (atlas *Atlas) Descent() float32 {
 
 // New returns a Atlas of a given TTF data, image dimensions, and a given slice of runes.
 func New(ttfData *[]byte, fontPt float64, imgWidth, imgHeight, pad int, runes []rune) Atlas {
+  return NewWithOptions(ttfData, runes, Options{
+    FontPt: fontPt,
+    Width: imgWidth,
+    Height: imgHeight,
+    Pad: pad,
+  })
+}
+
+// setMetrics fills in the size and placement information of an AtlasItem from its glyph bounds.
+func setMetrics(atlasItem *AtlasItem, bounds fixed.Rectangle26_6, advance fixed.Int26_6, pad, imgWidth, imgHeight int) {
+  minX := bounds.Min.X.Floor()
+  minY := bounds.Min.Y.Floor()
+  maxX := bounds.Max.X.Ceil()
+  maxY := bounds.Max.Y.Ceil()
+  atlasItem.Advance = fixedFloat(advance)
+  // fmt.Printf("%s {%v, %v} {%v, %v} %v\n", string(r), minX, minY, maxX, maxY, atlasItem.Advance)
+  
+  atlasItem.BearingX = fixedFloat(bounds.Min.X) - float32(pad)
+  atlasItem.Descent = float32(maxY) + (fixedFloat(bounds.Min.Y) - float32(minY)) + float32(pad)
+  // ^ not sure if tiny middle add is needed, still WIP
+  // fmt.Printf("%s x %v, descent %v\n", string(r), atlasItem.BearingX, atlasItem.Descent)
+  
+  glyphWidth := maxX - minX + pad*2
+  glyphHeight := maxY - minY + pad*2
+  
+  atlasItem.Width = glyphWidth
+  atlasItem.Height = glyphHeight
+  
+  atlasItem.PercentWidth = float32(glyphWidth) / float32(imgWidth)
+  atlasItem.PercentHeight = float32(glyphHeight) / float32(imgHeight)
+}
+
+// NewWithOptions returns a Atlas of a given TTF data and slice of runes, configured by opts.
+// Glyphs too large for a page with its Spacing, Border and MipLevels alignment are skipped.
+func NewWithOptions(ttfData *[]byte, runes []rune, opts Options) Atlas {
+  fontPt, imgWidth, imgHeight, pad := opts.FontPt, opts.Width, opts.Height, opts.Pad
+  pad += opts.Effects.pad()
+  lcd := opts.LCD.Order != LCDNone
+  if lcd {
+    // room for the LCD filter to spread coverage, with effects off the RGBA pages
+    pad++
+    opts.Effects.Storage = EffectPages
+  }
+  layers := opts.Effects.layers()
+  channels := len(layers) > 0 && opts.Effects.Storage == EffectChannels
+  effectPages := make([][]draw.Image, len(layers))
+  
   // create atlas
   var atlas Atlas
   atlas.FontPt = fontPt
+  atlas.FaceOptions = opts.FaceOptions
   atlas.ReloadFont(ttfData)
+  atlas.Coverage = opts.Coverage
+  atlas.Format = opts.Format
+  atlas.MipLevels = opts.MipLevels
+  atlas.Spacing, atlas.Border, atlas.UVMode = opts.Spacing, opts.Border, opts.UVMode
+  atlas.UVOrigin, atlas.UVUnits = opts.UVOrigin, opts.UVUnits
+  // stem darkening reaches past glyph bounds
+  pad += int(math.Ceil(float64(atlas.darkening() / 2)))
   atlas.Pad = pad
+  atlas.Effects = opts.Effects
+  atlas.LCD = opts.LCD
+  atlas.Embolden, atlas.Oblique = opts.Embolden, opts.Oblique
   atlas.Items = make(map[rune]*AtlasItem)
+  atlas.Glyphs = make(map[truetype.Index]*AtlasItem)
   
   // cycle through runes and add each
   for _, r := range runes {
@@ -332,38 +787,59 @@ func New(ttfData *[]byte, fontPt float64, imgWidth, imgHeight, pad int, runes []
     
     var atlasItem AtlasItem
     atlasItem.Rune = r
+    atlasItem.Glyph = atlas.Font.Index(r)
       
     bounds, advance, _ := atlas.Face.GlyphBounds(r)
-    minX := bounds.Min.X.Floor()
-    minY := bounds.Min.Y.Floor()
-    maxX := bounds.Max.X.Ceil()
-    maxY := bounds.Max.Y.Ceil()
-    atlasItem.Advance = fixedFloat(advance)
-    // fmt.Printf("%s {%v, %v} {%v, %v} %v\n", string(r), minX, minY, maxX, maxY, atlasItem.Advance)
-    
-    atlasItem.BearingX = fixedFloat(bounds.Min.X) - float32(pad)
-    atlasItem.Descent = float32(maxY) + (fixedFloat(bounds.Min.Y) - float32(minY)) + float32(pad)
-    // ^ not sure if tiny middle add is needed, still WIP
-    // fmt.Printf("%s x %v, descent %v\n", string(r), atlasItem.BearingX, atlasItem.Descent)
-    
-    glyphWidth := maxX - minX + pad*2
-    glyphHeight := maxY - minY + pad*2
+    if atlas.synthetic() {
+      // the face doesn't know about synthetic styles
+      bounds, advance, _ = atlas.glyphBounds(atlasItem.Glyph)
+    }
+    setMetrics(&atlasItem, bounds, advance, pad, imgWidth, imgHeight)
     
-    atlasItem.Width = glyphWidth
-    atlasItem.Height = glyphHeight
+    atlas.Items[atlasItem.Rune] = &atlasItem
+    if _, ok := atlas.Glyphs[atlasItem.Glyph]; !ok {
+      atlas.Glyphs[atlasItem.Glyph] = &atlasItem
+    }
+  }
+  
+  // add glyphs requested by index that no rune maps to
+  for _, index := range opts.Glyphs {
+    if _, ok := atlas.Glyphs[index]; ok {
+      continue
+    }
+    bounds, advance, err := atlas.glyphBounds(index)
+    if err != nil {
+      fmt.Println("ratlas: skipping glyph", index, err)
+      continue
+    }
     
-    atlasItem.PercentWidth = float32(glyphWidth) / float32(imgWidth)
-    atlasItem.PercentHeight = float32(glyphHeight) / float32(imgHeight)
+    var atlasItem AtlasItem
+    atlasItem.Rune = -1
+    atlasItem.Glyph = index
+    setMetrics(&atlasItem, bounds, advance, pad, imgWidth, imgHeight)
     
-    atlas.Items[atlasItem.Rune] = &atlasItem
+    atlas.Glyphs[index] = &atlasItem
   }
   
+  atlas.addVariants(opts.SubpixelVariants, pad, imgWidth, imgHeight)
+  atlas.dropOversized(imgWidth, imgHeight, mipAlign(opts.MipLevels), opts.Spacing, opts.Border)
+  
   // while we have glyphs that aren't on a sheet, create new sheets for them
   for atlas.containsNilNodes() {
     // create new atlas image sheet
-    atlas.Images = append(atlas.Images, image.NewGray(image.Rect(0, 0, imgWidth, imgHeight)))
+    sheet := image.Rect(0, 0, imgWidth, imgHeight)
+    if channels || lcd {
+      // starts transparent black, as alpha holds glow or LCD coverage
+      atlas.Images = append(atlas.Images, image.NewNRGBA(sheet))
+    } else {
+      atlas.Images = append(atlas.Images, atlas.Format.newPage(sheet))
+    }
     imageIndex := len(atlas.Images) - 1
-    draw.Draw(atlas.Images[imageIndex], atlas.Images[imageIndex].Bounds(), image.Black, image.Point{}, draw.Src)
+    if !channels {
+      for k := range effectPages {
+        effectPages[k] = append(effectPages[k], atlas.Format.newPage(sheet))
+      }
+    }
     
     // sort nil nodes per AtlasItems sort implementation
     itemSlice := atlas.getNilNodes()
@@ -371,42 +847,103 @@ func New(ttfData *[]byte, fontPt float64, imgWidth, imgHeight, pad int, runes []
     
     // give each rune a position within an image sheet
     // if it doesn't fit on current sheet, node remains nil
-    fitAtlasItems(itemSlice, imgWidth, imgHeight)
+    fitAtlasItems(itemSlice, imgWidth, imgHeight, mipAlign(opts.MipLevels), opts.Spacing, opts.Border)
+    placed := false
+    for _, atlasItem := range itemSlice {
+      placed = placed || atlasItem.Node != nil
+    }
+    if !placed {
+      // nothing fits on an empty page, and never will
+      fmt.Println("ratlas: couldn't place", len(itemSlice), "glyphs")
+      atlas.Images = atlas.Images[:imageIndex]
+      if !channels {
+        for k := range effectPages {
+          effectPages[k] = effectPages[k][:imageIndex]
+        }
+      }
+      for _, atlasItem := range itemSlice {
+        atlas.dropItem(atlasItem)
+      }
+      break
+    }
     
-    // attempt to copy AtlasItems into atlas sheet, until full
+    // copy AtlasItems that found room into atlas sheet; smaller items may fit after larger ones didn't
     for _, atlasItem := range itemSlice {
       if atlasItem.Node == nil {
-        break
+        continue
       }
       atlasItem.ImageIndex = imageIndex
       
-      bounds, _, _ := atlas.Face.GlyphBounds(atlasItem.Rune)
-      minX := bounds.Min.X.Floor()
-      // maxX := bounds.Max.X.Ceil()
-      minY := bounds.Min.Y.Floor()
-      // maxY := bounds.Max.Y.Ceil()
-      
       // create glyph image
       dst := image.NewGray(image.Rect(0, 0, atlasItem.Width, atlasItem.Height))
       draw.Draw(dst, dst.Bounds(), image.Black, image.Point{}, draw.Src)
       
       // render glyph to free standing glyph image
-      d := &font.Drawer{
-        Dst: dst,
-        Src: image.White,
-        Face: atlas.Face,
+      if atlasItem.Rune < 0 || atlas.synthetic() || atlasItem.phase != 0 || atlas.dilation() > 0 {
+        err := atlas.drawGlyph(dst, atlasItem.Glyph, pad, atlasItem.phase)
+        if err != nil {
+          fmt.Println(err)
+        }
+      } else {
+        bounds, _, _ := atlas.Face.GlyphBounds(atlasItem.Rune)
+        minX := bounds.Min.X.Floor()
+        // maxX := bounds.Max.X.Ceil()
+        minY := bounds.Min.Y.Floor()
+        // maxY := bounds.Max.Y.Ceil()
+        
+        d := &font.Drawer{
+          Dst: dst,
+          Src: image.White,
+          Face: atlas.Face,
+        }
+        d.Dot = fixed.P(-minX+pad, -minY+pad)
+        dr, mask, maskp, _, _ := d.Face.Glyph(d.Dot, atlasItem.Rune)
+        draw.DrawMask(d.Dst, dr, d.Src, image.Point{}, mask, maskp, draw.Over)
       }
-      d.Dot = fixed.P(-minX+pad, -minY+pad)
-    	dr, mask, maskp, _, _ := d.Face.Glyph(d.Dot, atlasItem.Rune)
-      draw.DrawMask(d.Dst, dr, d.Src, image.Point{}, mask, maskp, draw.Over)
       
-      // copy glyph image to atlas image
-      draw.Draw(atlas.Images[imageIndex], image.Rect(atlasItem.Node.X, atlasItem.Node.Y, atlasItem.Node.X+atlasItem.Width, atlasItem.Node.Y+atlasItem.Height), dst, image.Point{}, draw.Src)
+      // copy glyph image and its effects to atlas images
+      rect := image.Rect(atlasItem.Node.X, atlasItem.Node.Y, atlasItem.Node.X+atlasItem.Width, atlasItem.Node.Y+atlasItem.Height)
+      var baked [4]*image.Gray
+      if len(layers) > 0 {
+        var err error
+        baked, err = atlas.bakeEffects(dst, atlasItem.Glyph, pad, atlasItem.phase)
+        if err != nil {
+          fmt.Println(err)
+        }
+      }
+      // effects spread from unadjusted coverage
+      atlas.Coverage.adjust(dst.Pix)
+      if channels {
+        packChannels(atlas.Images[imageIndex].(*image.NRGBA), rect, baked)
+      } else {
+        if lcd {
+          err := atlas.drawLCD(atlas.Images[imageIndex].(*image.NRGBA), rect, atlasItem.Glyph, pad, atlasItem.phase)
+          if err != nil {
+            fmt.Println(err)
+          }
+        } else if atlas.Format == FormatGray16 {
+          // rasterized again at 16 bits
+          page := atlas.Images[imageIndex].(*image.Gray16).SubImage(rect).(*image.Gray16)
+          err := atlas.drawGlyph(page, atlasItem.Glyph, pad, atlasItem.phase)
+          if err != nil {
+            fmt.Println(err)
+          }
+          atlas.Coverage.adjust16(page)
+        } else {
+          copyCoverage(atlas.Images[imageIndex], rect, dst)
+        }
+        for k, effect := range layers {
+          copyCoverage(effectPages[k][imageIndex], rect, baked[effect])
+        }
+      }
       
-      atlasItem.PercentPosX = float32(atlasItem.Node.X) / float32(imgWidth)
-      atlasItem.PercentPosY = float32(atlasItem.Node.Y) / float32(imgHeight)
+      atlas.setPercents(atlasItem, imgWidth, imgHeight)
     }
   }
+  atlas.finishVariants()
+  for _, pages := range effectPages {
+    atlas.Images = append(atlas.Images, pages...)
+  }
   
   return atlas
 }
@@ -0,0 +1,125 @@
+package ratlas
+
+import (
+  "image"
+  "io/ioutil"
+  "testing"
+  "time"
+)
+
+func testFont(t *testing.T) []byte {
+  data, err := ioutil.ReadFile("example/Vera.ttf")
+  if err != nil {
+    t.Fatal(err)
+  }
+  return data
+}
+
+// itemRect returns the texels of a packed item.
+func itemRect(item *AtlasItem) image.Rectangle {
+  return image.Rect(item.Node.X, item.Node.Y, item.Node.X+item.Width, item.Node.Y+item.Height)
+}
+
+// TestDropOversized builds an atlas with a glyph larger than its pages,
+// which must be dropped rather than add pages forever, while the others are
+// placed.
+func TestDropOversized(t *testing.T) {
+  data := testFont(t)
+  done := make(chan Atlas)
+  go func() {
+    done <- NewWithOptions(&data, []rune("W.,-"), Options{FontPt: 64, Width: 40, Height: 40, Pad: 2})
+  }()
+  var atlas Atlas
+  select {
+  case atlas = <-done:
+  case <-time.After(30 * time.Second):
+    t.Fatal("atlas construction doesn't end")
+  }
+
+  if _, ok := atlas.Items['W']; ok {
+    t.Errorf("W is in the atlas")
+  }
+  for _, r := range ".,-" {
+    item, ok := atlas.Items[r]
+    if !ok || item.Node == nil {
+      t.Errorf("%q isn't placed", r)
+      continue
+    }
+    if !itemRect(item).In(atlas.Images[item.ImageIndex].Bounds()) {
+      t.Errorf("%q at %v, off its page", r, itemRect(item))
+    }
+  }
+  if len(atlas.Images) != 1 {
+    t.Errorf("%d pages, want 1", len(atlas.Images))
+  }
+}
+
+// TestUVMode checks that item UVs span the outer edges of their texels with
+// UVTexelEdges, and the centers of the edge texels with UVHalfTexelInset.
+func TestUVMode(t *testing.T) {
+  data := testFont(t)
+  const w, h = 128, 128
+  for _, mode := range []UVMode{UVTexelEdges, UVHalfTexelInset} {
+    atlas := NewWithOptions(&data, []rune("AgQ|."), Options{FontPt: 24, Width: w, Height: h, Pad: 2, UVMode: mode})
+    inset := float32(0)
+    if mode == UVHalfTexelInset {
+      inset = 0.5
+    }
+    for r, item := range atlas.Items {
+      rect := itemRect(item)
+      x0, y0 := float32(rect.Min.X)+inset, float32(rect.Min.Y)+inset
+      x1, y1 := float32(rect.Max.X)-inset, float32(rect.Max.Y)-inset
+      if item.UVInset != inset {
+        t.Errorf("mode %d: %q has inset %v, want %v", mode, r, item.UVInset, inset)
+      }
+      if item.U0*w != x0 || item.V0*h != y0 || item.U1*w != x1 || item.V1*h != y1 {
+        t.Errorf("mode %d: %q has UVs (%v, %v)-(%v, %v) on texels %v, want (%v, %v)-(%v, %v) in texels", mode, r, item.U0*w, item.V0*h, item.U1*w, item.V1*h, rect, x0, y0, x1, y1)
+      }
+      if item.PercentPosX != item.U0 || item.PercentPosY != item.V0 || item.PercentWidth != item.U1-item.U0 || item.PercentHeight != item.V1-item.V0 {
+        t.Errorf("mode %d: %q has Percent fields that disagree with its UVs", mode, r)
+      }
+    }
+  }
+}
+
+// TestSpacingBorder checks that Spacing keeps the texels of glyphs apart and
+// Border keeps them away from the page edges.
+func TestSpacingBorder(t *testing.T) {
+  data := testFont(t)
+  var runes []rune
+  for r := rune('!'); r <= '~'; r++ {
+    runes = append(runes, r)
+  }
+  const w, h = 128, 128
+  for _, opts := range []Options{
+    {Spacing: 3, Border: 5},
+    {Spacing: 1, Border: 3, MipLevels: 3},
+    {Spacing: 0, Border: 1},
+  } {
+    opts.FontPt, opts.Width, opts.Height, opts.Pad = 20, w, h, 1
+    atlas := NewWithOptions(&data, runes, opts)
+    if len(atlas.Images) < 2 {
+      t.Errorf("%+v: %d pages, want several", opts, len(atlas.Images))
+    }
+    inner := image.Rect(opts.Border, opts.Border, w-opts.Border, h-opts.Border)
+    var items []*AtlasItem
+    for _, r := range runes {
+      item := atlas.Items[r]
+      if item == nil || item.Node == nil {
+        t.Fatalf("%+v: %q isn't placed", opts, r)
+      }
+      if !itemRect(item).In(inner) {
+        t.Errorf("%+v: %q at %v, within the border", opts, r, itemRect(item))
+      }
+      items = append(items, item)
+    }
+    for i, a := range items {
+      grown := itemRect(a).Inset(-opts.Spacing)
+      for _, b := range items[i+1:] {
+        if a.ImageIndex == b.ImageIndex && grown.Overlaps(itemRect(b)) {
+          t.Errorf("%+v: %q at %v and %q at %v are less than %d apart", opts, a.Rune, itemRect(a), b.Rune, itemRect(b), opts.Spacing)
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,265 @@
+// Package render draws laid out text onto images on the CPU, sampling atlas
+// images bilinearly as a GPU would, for servers, thumbnails and golden image
+// tests.
+package render
+
+import (
+  "image"
+  "image/color"
+  "image/draw"
+  "math"
+
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/layout"
+)
+
+// Mode is what atlas texels hold.
+type Mode int
+
+const (
+  // Coverage atlases hold glyph coverage, as ratlas.New bakes them.
+  Coverage Mode = iota
+
+  // SDF atlases hold signed distance fields, with Threshold at glyph edges.
+  SDF
+
+  // LCD atlases hold per-channel coverage, as ratlas bakes them with
+  // Options.LCD, blended channel by channel.
+  LCD
+)
+
+// Renderer draws glyphs from an atlas.
+type Renderer struct {
+  Atlas *ratlas.Atlas
+  Mode  Mode
+
+  // Threshold is the SDF value at glyph edges, 0.5 if zero.
+  Threshold float32
+
+  // Smoothing is the SDF distance over which edges fade at a glyph scale of
+  // 1, and is divided by the scale as in the package mesh shaders; zero
+  // means 1/16.
+  Smoothing float32
+
+  // Effect is the atlas layer drawn, such as ratlas.EffectShadow to draw
+  // shadows before the text over them. Atlases without it draw nothing.
+  Effect ratlas.Effect
+}
+
+// New returns a Renderer drawing from atlas.
+func New(atlas *ratlas.Atlas, mode Mode) *Renderer {
+  return &Renderer{Atlas: atlas, Mode: mode}
+}
+
+// DrawText draws the glyphs of a layout result in color c, with the box's
+// top left corner at (x, y) in dst.
+func (r *Renderer) DrawText(dst draw.Image, res *layout.Result, x, y float32, c color.Color) {
+  for _, g := range res.Glyphs {
+    r.DrawGlyph(dst, r.Atlas, g, x, y, c)
+  }
+}
+
+// DrawSpans draws the glyphs and underlines of a result laid out by
+// layout.LayoutSpans with the atlases and colors of their spans, or r.Atlas
+// and c for spans without them.
+func (r *Renderer) DrawSpans(dst draw.Image, res *layout.Result, spans []layout.Span, x, y float32, c color.Color) {
+  for _, g := range res.Glyphs {
+    atlas, gc := r.Atlas, c
+    if g.Span < len(spans) {
+      style := spans[g.Span].Style
+      if style.Atlas != nil {
+        atlas = style.Atlas
+      }
+      if style.Color != nil {
+        gc = style.Color
+      }
+    }
+    r.DrawGlyph(dst, atlas, g, x, y, gc)
+  }
+  for _, u := range res.Underlines {
+    uc := c
+    if u.Span < len(spans) && spans[u.Span].Style.Color != nil {
+      uc = spans[u.Span].Style.Color
+    }
+    FillRect(dst, ratlas.Bounds{X0: u.X0 + x, Y0: u.Y0 + y, X1: u.X1 + x, Y1: u.Y1 + y}, uc)
+  }
+}
+
+// FillRect fills a rectangle in color c, antialiasing its edges by the area
+// of each pixel it covers.
+func FillRect(dst draw.Image, b ratlas.Bounds, c color.Color) {
+  cr, cg, cb, ca := c.RGBA()
+  rect := image.Rect(int(math.Floor(float64(b.X0))), int(math.Floor(float64(b.Y0))), int(math.Ceil(float64(b.X1))), int(math.Ceil(float64(b.Y1))))
+  rect = rect.Intersect(dst.Bounds())
+  overlap := func(lo, hi float32, p int) float32 {
+    return clamp(hi, float32(p), float32(p+1)) - clamp(lo, float32(p), float32(p+1))
+  }
+  for py := rect.Min.Y; py < rect.Max.Y; py++ {
+    for px := rect.Min.X; px < rect.Max.X; px++ {
+      if alpha := overlap(b.X0, b.X1, px) * overlap(b.Y0, b.Y1, py); alpha > 0 {
+        blend(dst, px, py, cr, cg, cb, ca, alpha)
+      }
+    }
+  }
+}
+
+// DrawGlyph draws a glyph from atlas in color c, offset by (x, y).
+func (r *Renderer) DrawGlyph(dst draw.Image, atlas *ratlas.Atlas, g layout.Glyph, x, y float32, c color.Color) {
+  item := g.Item
+  if item == nil || item.Width == 0 || item.Height == 0 || item.ImageIndex >= len(atlas.Images) {
+    return
+  }
+  src, channel, ok := atlas.EffectImage(item, r.Effect)
+  if !ok {
+    return
+  }
+  size := src.Bounds().Size()
+  q := g.Quad()
+  q.X0, q.Y0, q.X1, q.Y1 = q.X0+x, q.Y0+y, q.X1+x, q.Y1+y
+
+  // the glyph's texels, which samples don't reach past, and the quad's
+  // inset into them
+  inset := item.UVInset
+  tx0 := float32(math.Floor(float64(item.PercentPosX*float32(size.X)-inset) + 0.5))
+  ty0 := float32(math.Floor(float64(item.PercentPosY*float32(size.Y)-inset) + 0.5))
+  tx1 := tx0 + float32(item.Width) - 1
+  ty1 := ty0 + float32(item.Height) - 1
+
+  threshold, smoothing := r.Threshold, r.Smoothing
+  if threshold == 0 {
+    threshold = 0.5
+  }
+  if smoothing == 0 {
+    smoothing = 1.0 / 16
+  }
+  if g.Scale > 0 {
+    smoothing /= g.Scale
+  }
+
+  cr, cg, cb, ca := c.RGBA()
+  rect := image.Rect(int(math.Floor(float64(q.X0))), int(math.Floor(float64(q.Y0))), int(math.Ceil(float64(q.X1))), int(math.Ceil(float64(q.Y1))))
+  rect = rect.Intersect(dst.Bounds())
+  for py := rect.Min.Y; py < rect.Max.Y; py++ {
+    ty := ty0 + inset + (float32(py)+0.5-q.Y0)/(q.Y1-q.Y0)*(float32(item.Height)-2*inset) - 0.5
+    for px := rect.Min.X; px < rect.Max.X; px++ {
+      tx := tx0 + inset + (float32(px)+0.5-q.X0)/(q.X1-q.X0)*(float32(item.Width)-2*inset) - 0.5
+      sx, sy := clamp(tx, tx0, tx1), clamp(ty, ty0, ty1)
+      if r.Mode == LCD {
+        blendLCD(dst, px, py, c, bilinear(src, 0, sx, sy), bilinear(src, 1, sx, sy), bilinear(src, 2, sx, sy))
+        continue
+      }
+      v := bilinear(src, channel, sx, sy)
+      alpha := v
+      if r.Mode == SDF {
+        alpha = smoothstep(threshold-smoothing, threshold+smoothing, v)
+      }
+      if alpha <= 0 {
+        continue
+      }
+      blend(dst, px, py, cr, cg, cb, ca, alpha)
+    }
+  }
+}
+
+func clamp(v, lo, hi float32) float32 {
+  if v < lo {
+    return lo
+  }
+  if v > hi {
+    return hi
+  }
+  return v
+}
+
+func smoothstep(e0, e1, v float32) float32 {
+  t := clamp((v-e0)/(e1-e0), 0, 1)
+  return t * t * (3 - 2*t)
+}
+
+// texel returns a channel of img at (x, y), from 0 to 1, with 0 to 3 being
+// red to alpha. Channels aren't premultiplied, as they may hold unrelated
+// layers.
+func texel(img image.Image, channel, x, y int) float32 {
+  switch img := img.(type) {
+  case *image.Gray:
+    return float32(img.GrayAt(x, y).Y) / 255
+  case *image.Gray16:
+    return float32(img.Gray16At(x, y).Y) / 0xFFFF
+  case *image.Alpha:
+    // coverage is alpha, whichever channel is asked for
+    return float32(img.AlphaAt(x, y).A) / 255
+  case *image.NRGBA:
+    return float32(img.Pix[img.PixOffset(x, y)+channel]) / 255
+  case *image.RGBA:
+    // premultiplied white holds coverage in every channel
+    return float32(img.Pix[img.PixOffset(x, y)+channel]) / 255
+  }
+  c := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64)
+  return float32([]uint16{c.R, c.G, c.B, c.A}[channel]) / 0xFFFF
+}
+
+// bilinear samples a channel of img at texel coordinates (x, y), where texel
+// centers are at whole numbers.
+func bilinear(img image.Image, channel int, x, y float32) float32 {
+  x0, y0 := int(math.Floor(float64(x))), int(math.Floor(float64(y)))
+  fx, fy := x-float32(x0), y-float32(y0)
+  b := img.Bounds()
+  x1, y1 := x0+1, y0+1
+  if x1 >= b.Max.X {
+    x1 = x0
+  }
+  if y1 >= b.Max.Y {
+    y1 = y0
+  }
+  top := texel(img, channel, x0, y0)*(1-fx) + texel(img, channel, x1, y0)*fx
+  bottom := texel(img, channel, x0, y1)*(1-fx) + texel(img, channel, x1, y1)*fx
+  return top*(1-fy) + bottom*fy
+}
+
+// blend composites the premultiplied color (r, g, b, a) at coverage alpha
+// over dst's pixel at (x, y).
+func blend(dst draw.Image, x, y int, r, g, b, a uint32, alpha float32) {
+  m := uint32(alpha*0xFFFF + 0.5)
+  r, g, b, a = r*m/0xFFFF, g*m/0xFFFF, b*m/0xFFFF, a*m/0xFFFF
+  inv := 0xFFFF - a
+
+  if rgba, ok := dst.(*image.RGBA); ok {
+    i := rgba.PixOffset(x, y)
+    p := rgba.Pix[i : i+4 : i+4]
+    p[0] = uint8((uint32(p[0])*0x101*inv/0xFFFF + r) >> 8)
+    p[1] = uint8((uint32(p[1])*0x101*inv/0xFFFF + g) >> 8)
+    p[2] = uint8((uint32(p[2])*0x101*inv/0xFFFF + b) >> 8)
+    p[3] = uint8((uint32(p[3])*0x101*inv/0xFFFF + a) >> 8)
+    return
+  }
+  dr, dg, db, da := dst.At(x, y).RGBA()
+  dst.Set(x, y, color.RGBA64{
+    R: uint16(dr*inv/0xFFFF + r),
+    G: uint16(dg*inv/0xFFFF + g),
+    B: uint16(db*inv/0xFFFF + b),
+    A: uint16(da*inv/0xFFFF + a),
+  })
+}
+
+// blendLCD composites c over dst's pixel at (x, y) with separate red, green
+// and blue coverage, as dual-source blending does: each channel of the
+// premultiplied color has its own alpha. Alpha is covered by the greatest of
+// the three, so partly transparent pixels stay valid premultiplied colors.
+func blendLCD(dst draw.Image, x, y int, c color.Color, r, g, b float32) {
+  if r <= 0 && g <= 0 && b <= 0 {
+    return
+  }
+  sr, sg, sb, sa := c.RGBA()
+  a := float32(sa) / 0xFFFF
+  mix := func(d, s uint32, coverage float32) uint16 {
+    coverage = clamp(coverage, 0, 1)
+    return uint16(clamp(float32(d)*(1-coverage*a)+float32(s)*coverage+0.5, 0, 0xFFFF))
+  }
+  dr, dg, db, da := dst.At(x, y).RGBA()
+  dst.Set(x, y, color.RGBA64{
+    R: mix(dr, sr, r),
+    G: mix(dg, sg, g),
+    B: mix(db, sb, b),
+    A: mix(da, sa, float32(math.Max(float64(r), math.Max(float64(g), float64(b))))),
+  })
+}
@@ -0,0 +1,276 @@
+package render
+
+import (
+  "image"
+  "image/color"
+  "image/draw"
+  "io/ioutil"
+  "math"
+  "testing"
+
+  "github.com/vrav/ratlas"
+  "github.com/vrav/ratlas/layout"
+)
+
+// inkBounds returns the bounds of the pixels of img with any alpha, and the
+// sum of their alpha from 0 to 1.
+func inkBounds(img image.Image) (image.Rectangle, float64) {
+  var ink image.Rectangle
+  var sum float64
+  b := img.Bounds()
+  for y := b.Min.Y; y < b.Max.Y; y++ {
+    for x := b.Min.X; x < b.Max.X; x++ {
+      _, _, _, a := img.At(x, y).RGBA()
+      if a == 0 {
+        continue
+      }
+      ink = ink.Union(image.Rect(x, y, x+1, y+1))
+      sum += float64(a) / 0xFFFF
+    }
+  }
+  return ink, sum
+}
+
+// TestDrawText draws a string at the atlas size, where each texel should map
+// to a pixel, and checks that the ink lands where Atlas.Measure puts it and
+// that no coverage is lost or gained.
+func TestDrawText(t *testing.T) {
+  data, err := ioutil.ReadFile("../example/Vera.ttf")
+  if err != nil {
+    t.Fatal(err)
+  }
+  const text, size = "Hog", 32
+  atlas := ratlas.NewWithOptions(&data, []rune(text), ratlas.Options{FontPt: size, Width: 256, Height: 256, Pad: 2})
+  res := layout.Layout(&atlas, text, layout.Options{Size: size})
+
+  // the coverage baked for the text
+  var baked float64
+  for _, r := range text {
+    item := atlas.Items[r]
+    page := atlas.Images[item.ImageIndex].(*image.Gray)
+    for y := item.Node.Y; y < item.Node.Y+item.Height; y++ {
+      for x := item.Node.X; x < item.Node.X+item.Width; x++ {
+        baked += float64(page.GrayAt(x, y).Y) / 0xFF
+      }
+    }
+  }
+  metrics := atlas.Measure(text, size)
+  const x, y = 10, 20
+  want := image.Rect(
+    int(math.Floor(float64(x+metrics.Ink.X0))), int(math.Floor(float64(y+metrics.Ink.Y0))),
+    int(math.Ceil(float64(x+metrics.Ink.X1))), int(math.Ceil(float64(y+metrics.Ink.Y1))))
+
+  for _, test := range []struct {
+    name string
+    dst  draw.Image
+    c    color.Color
+  }{
+    {"Gray", image.NewGray(image.Rect(0, 0, 120, 80)), color.White},
+    {"RGBA", image.NewRGBA(image.Rect(0, 0, 120, 80)), color.NRGBA{255, 0, 0, 255}},
+  } {
+    New(&atlas, Coverage).DrawText(test.dst, res, x, y, test.c)
+    var ink image.Rectangle
+    var sum float64
+    if gray, ok := test.dst.(*image.Gray); ok {
+      // coverage is brightness on black
+      alpha := image.NewAlpha(gray.Bounds())
+      copy(alpha.Pix, gray.Pix)
+      ink, sum = inkBounds(alpha)
+    } else {
+      ink, sum = inkBounds(test.dst)
+    }
+
+    // bilinear sampling may spread ink by a pixel
+    if ink.Empty() || !ink.In(want.Inset(-1)) || !want.Inset(1).In(ink) {
+      t.Errorf("%s: ink at %v, want %v", test.name, ink, want)
+    }
+    if math.Abs(sum-baked) > baked*0.02 {
+      t.Errorf("%s: coverage %.1f, baked %.1f", test.name, sum, baked)
+    }
+  }
+
+  // red stays red at every coverage
+  dst := image.NewRGBA(image.Rect(0, 0, 120, 80))
+  New(&atlas, Coverage).DrawText(dst, res, x, y, color.NRGBA{255, 0, 0, 255})
+  for i := 0; i < len(dst.Pix); i += 4 {
+    if p := dst.Pix[i : i+4]; p[0] != p[3] || p[1] != 0 || p[2] != 0 {
+      t.Fatalf("pixel %d is %v, want premultiplied red", i/4, p)
+    }
+  }
+}
+
+// TestBlendLCD blends white with red-only coverage, and with full coverage,
+// over opaque and transparent pixels, which must stay valid premultiplied
+// colors.
+func TestBlendLCD(t *testing.T) {
+  for _, test := range []struct {
+    dst     color.RGBA
+    r, g, b float32
+    want    color.RGBA
+  }{
+    {color.RGBA{0, 0, 0, 255}, 1, 0, 0, color.RGBA{255, 0, 0, 255}},
+    {color.RGBA{0, 0, 0, 255}, 1, 1, 1, color.RGBA{255, 255, 255, 255}},
+    {color.RGBA{0, 0, 255, 255}, 0.5, 0, 0, color.RGBA{128, 0, 255, 255}},
+    {color.RGBA{0, 0, 0, 0}, 1, 0, 0, color.RGBA{255, 0, 0, 255}},
+    {color.RGBA{0, 0, 0, 0}, 0.5, 0.25, 0, color.RGBA{128, 64, 0, 128}},
+    {color.RGBA{0, 0, 0, 0}, 1, 1, 1, color.RGBA{255, 255, 255, 255}},
+    {color.RGBA{0, 0, 0, 0}, 0, 0, 0, color.RGBA{0, 0, 0, 0}},
+    {color.RGBA{0, 0, 64, 64}, 0.5, 0, 0, color.RGBA{128, 0, 64, 160}},
+  } {
+    dst := image.NewRGBA(image.Rect(0, 0, 1, 1))
+    dst.SetRGBA(0, 0, test.dst)
+    blendLCD(dst, 0, 0, color.White, test.r, test.g, test.b)
+    got := dst.RGBAAt(0, 0)
+    if got != test.want {
+      t.Errorf("white at (%v, %v, %v) over %v: %v, want %v", test.r, test.g, test.b, test.dst, got, test.want)
+    }
+    if got.R > got.A || got.G > got.A || got.B > got.A {
+      t.Errorf("white at (%v, %v, %v) over %v: %v isn't premultiplied", test.r, test.g, test.b, test.dst, got)
+    }
+  }
+
+  // a translucent color is premultiplied before blending
+  dst := image.NewRGBA(image.Rect(0, 0, 1, 1))
+  blendLCD(dst, 0, 0, color.NRGBA{255, 255, 255, 128}, 1, 0, 0)
+  if got := dst.RGBAAt(0, 0); got != (color.RGBA{128, 0, 0, 128}) {
+    t.Errorf("translucent white over transparent black: %v", got)
+  }
+}
+
+// rampAtlas returns an atlas of one glyph, 64x4 texels, whose distance field
+// rises from left to right, crossing 0.5 halfway.
+func rampAtlas() (*ratlas.Atlas, layout.Glyph) {
+  const w, h = 64, 4
+  page := image.NewGray16(image.Rect(0, 0, w, h))
+  for y := 0; y < h; y++ {
+    for x := 0; x < w; x++ {
+      page.SetGray16(x, y, color.Gray16{uint16((float64(x) + 0.5) / w * 0xFFFF)})
+    }
+  }
+  item := &ratlas.AtlasItem{Width: w, Height: h, PercentWidth: 1, PercentHeight: 1, U1: 1, V1: 1}
+  return &ratlas.Atlas{Images: []draw.Image{page}}, layout.Glyph{Item: item, Scale: 1}
+}
+
+// TestDrawSDF draws a distance field ramp and checks the alpha of each pixel
+// against the smoothstep edge around the threshold, at several thresholds,
+// smoothings and scales.
+func TestDrawSDF(t *testing.T) {
+  atlas, g := rampAtlas()
+  for _, test := range []struct {
+    threshold, smoothing, scale float32
+  }{
+    {0, 0, 1},
+    {0.25, 0, 1},
+    {0, 0.125, 1},
+    {0, 0, 2},
+    {0.75, 0.03125, 0.5},
+  } {
+    r := New(atlas, SDF)
+    r.Threshold, r.Smoothing = test.threshold, test.smoothing
+    threshold, smoothing := test.threshold, test.smoothing
+    if threshold == 0 {
+      threshold = 0.5
+    }
+    if smoothing == 0 {
+      smoothing = 1.0 / 16
+    }
+    smoothing /= test.scale
+    g.Scale = test.scale
+    dst := image.NewRGBA(image.Rect(0, 0, 140, 10))
+    r.DrawGlyph(dst, atlas, g, 0, 10, color.White)
+
+    edge := -1
+    for x := 0; x < int(64*test.scale); x++ {
+      // the texel sampled, and the ramp's value there
+      tx := (float64(x)+0.5)/float64(test.scale) - 0.5
+      v := float32((math.Max(0, math.Min(63, tx)) + 0.5) / 64)
+      want := smoothstep(threshold-smoothing, threshold+smoothing, v)
+      got := float32(dst.RGBAAt(x, 8).A) / 255
+      if math.Abs(float64(got-want)) > 1.0/255 {
+        t.Errorf("%+v: pixel %d has alpha %v, want %v", test, x, got, want)
+      }
+      switch {
+      case v <= threshold-smoothing && got != 0:
+        t.Errorf("%+v: pixel %d outside the edge has alpha %v", test, x, got)
+      case v >= threshold+smoothing && got != 1:
+        t.Errorf("%+v: pixel %d inside the edge has alpha %v", test, x, got)
+      }
+      if edge < 0 && got >= 0.5 {
+        edge = x
+      }
+    }
+    // the edge is where the field crosses the threshold
+    if want := threshold * 64 * test.scale; math.Abs(float64(edge)-float64(want)) > 1 {
+      t.Errorf("%+v: edge at pixel %d, want %v", test, edge, want)
+    }
+  }
+}
+
+// TestDrawLCD draws text from an LCD atlas and checks that each channel gets
+// its own coverage, as much as was baked for it, and that color channels
+// without coverage are left alone.
+func TestDrawLCD(t *testing.T) {
+  data, err := ioutil.ReadFile("../example/Vera.ttf")
+  if err != nil {
+    t.Fatal(err)
+  }
+  const text, size = "Hog", 32
+  atlas := ratlas.NewWithOptions(&data, []rune(text), ratlas.Options{FontPt: size, Width: 256, Height: 256, Pad: 2, LCD: ratlas.LCD{Order: ratlas.LCDRGB}})
+  res := layout.Layout(&atlas, text, layout.Options{Size: size})
+
+  // the coverage baked for the text, per channel
+  var baked [3]float64
+  for _, r := range text {
+    item := atlas.Items[r]
+    page := atlas.Images[item.ImageIndex].(*image.NRGBA)
+    for y := item.Node.Y; y < item.Node.Y+item.Height; y++ {
+      for x := item.Node.X; x < item.Node.X+item.Width; x++ {
+        for c := range baked {
+          baked[c] += float64(page.Pix[page.PixOffset(x, y)+c]) / 0xFF
+        }
+      }
+    }
+  }
+
+  // white over black, opaque and transparent
+  for _, alpha := range []uint8{255, 0} {
+    dst := image.NewRGBA(image.Rect(0, 0, 120, 80))
+    for i := 3; i < len(dst.Pix); i += 4 {
+      dst.Pix[i] = alpha
+    }
+    New(&atlas, LCD).DrawText(dst, res, 10, 20, color.White)
+    var sum [3]float64
+    fringes := 0
+    for i := 0; i < len(dst.Pix); i += 4 {
+      p := dst.Pix[i : i+4]
+      for c := range sum {
+        sum[c] += float64(p[c]) / 0xFF
+      }
+      if p[0] != p[1] || p[1] != p[2] {
+        fringes++
+      }
+      if p[0] > p[3] || p[1] > p[3] || p[2] > p[3] || (alpha == 255 && p[3] != 255) {
+        t.Fatalf("alpha %d: pixel %d is %v", alpha, i/4, p)
+      }
+    }
+    for c := range sum {
+      if math.Abs(sum[c]-baked[c]) > baked[c]*0.02 {
+        t.Errorf("alpha %d: channel %d coverage %.1f, baked %.1f", alpha, c, sum[c], baked[c])
+      }
+    }
+    if fringes == 0 {
+      t.Errorf("alpha %d: channels all have the same coverage", alpha)
+    }
+  }
+
+  // red only reaches the red channel, with its own coverage
+  dst := image.NewRGBA(image.Rect(0, 0, 120, 80))
+  white := image.NewRGBA(dst.Bounds())
+  New(&atlas, LCD).DrawText(dst, res, 10, 20, color.NRGBA{255, 0, 0, 255})
+  New(&atlas, LCD).DrawText(white, res, 10, 20, color.White)
+  for i := 0; i < len(dst.Pix); i += 4 {
+    if p := dst.Pix[i : i+4]; p[0] != white.Pix[i] || p[1] != 0 || p[2] != 0 {
+      t.Fatalf("pixel %d is %v, want red of %v", i/4, p, white.Pix[i:i+4])
+    }
+  }
+}
@@ -0,0 +1,123 @@
+package shaping
+
+import (
+  "unicode"
+)
+
+// joining types from Unicode ArabicShaping.txt
+type joiningType uint8
+
+const (
+  joinNone joiningType = iota
+  joinRight
+  joinDual
+  joinCausing
+  joinTransparent
+)
+
+// form is the positional form a joining letter takes within a word.
+type form uint8
+
+const (
+  formNone form = iota
+  formIsol
+  formFina
+  formMedi
+  formInit
+)
+
+// joinRanges lists the dual and right joining letters of the Arabic and
+// Arabic Supplement blocks. Letters not listed are non-joining.
+var joinRanges = []struct {
+  lo, hi rune
+  t      joiningType
+}{
+  {0x0620, 0x0620, joinDual},
+  {0x0622, 0x0625, joinRight},
+  {0x0626, 0x0626, joinDual},
+  {0x0627, 0x0627, joinRight},
+  {0x0628, 0x0628, joinDual},
+  {0x0629, 0x0629, joinRight},
+  {0x062A, 0x062E, joinDual},
+  {0x062F, 0x0632, joinRight},
+  {0x0633, 0x063F, joinDual},
+  {0x0640, 0x0640, joinCausing},
+  {0x0641, 0x0647, joinDual},
+  {0x0648, 0x0648, joinRight},
+  {0x0649, 0x064A, joinDual},
+  {0x066E, 0x066F, joinDual},
+  {0x0671, 0x0673, joinRight},
+  {0x0675, 0x0677, joinRight},
+  {0x0678, 0x0687, joinDual},
+  {0x0688, 0x0699, joinRight},
+  {0x069A, 0x06BF, joinDual},
+  {0x06C0, 0x06C0, joinRight},
+  {0x06C1, 0x06C2, joinDual},
+  {0x06C3, 0x06CB, joinRight},
+  {0x06CC, 0x06CC, joinDual},
+  {0x06CD, 0x06CD, joinRight},
+  {0x06CE, 0x06CE, joinDual},
+  {0x06CF, 0x06CF, joinRight},
+  {0x06D0, 0x06D1, joinDual},
+  {0x06D2, 0x06D3, joinRight},
+  {0x06D5, 0x06D5, joinRight},
+  {0x06EE, 0x06EF, joinRight},
+  {0x06FA, 0x06FC, joinDual},
+  {0x06FF, 0x06FF, joinDual},
+  {0x0750, 0x0758, joinDual},
+  {0x0759, 0x075B, joinRight},
+  {0x075C, 0x076A, joinDual},
+  {0x076B, 0x076C, joinRight},
+  {0x076D, 0x0770, joinDual},
+  {0x0771, 0x0771, joinRight},
+  {0x0772, 0x0772, joinDual},
+  {0x0773, 0x0774, joinRight},
+  {0x0775, 0x0777, joinDual},
+  {0x0778, 0x0779, joinRight},
+  {0x077A, 0x077F, joinDual},
+  {0x200D, 0x200D, joinCausing},
+}
+
+func joiningTypeOf(r rune) joiningType {
+  for _, jr := range joinRanges {
+    if r >= jr.lo && r <= jr.hi {
+      return jr.t
+    }
+  }
+  if r != 0x200C && (unicode.In(r, unicode.Mn, unicode.Me) || unicode.Is(unicode.Cf, r)) {
+    return joinTransparent
+  }
+  return joinNone
+}
+
+// arabicForms computes the positional form of each rune in logical order.
+// Transparent runes (marks) don't interrupt joining and get no form.
+func arabicForms(runes []rune) []form {
+  forms := make([]form, len(runes))
+  types := make([]joiningType, len(runes))
+  prev := -1
+  for i, r := range runes {
+    t := joiningTypeOf(r)
+    types[i] = t
+    if t == joinTransparent {
+      continue
+    }
+    if t == joinDual || t == joinRight {
+      forms[i] = formIsol
+    }
+    if prev >= 0 && (types[prev] == joinDual || types[prev] == joinCausing) &&
+      (t == joinDual || t == joinRight || t == joinCausing) {
+      switch forms[prev] {
+      case formIsol:
+        forms[prev] = formInit
+      case formFina:
+        forms[prev] = formMedi
+      }
+      if t != joinCausing {
+        forms[i] = formFina
+      }
+    }
+    prev = i
+  }
+  return forms
+}
@@ -0,0 +1,216 @@
+package shaping
+
+import (
+  "github.com/golang/freetype/truetype"
+)
+
+// matchFunc reports whether glyph g matches the k-th value of a sequence.
+type matchFunc func(k int, g truetype.Index) bool
+
+// matchInput matches n glyphs following position i, skipping glyphs ignored
+// by the current lookup flag. It returns the positions of i and the matches.
+func (s *shaper) matchInput(i, n int, match matchFunc) ([]int, bool) {
+  positions := []int{i}
+  j := i
+  for k := 0; k < n; k++ {
+    j = s.next(j)
+    if j < 0 || !match(k, s.info[j].id) {
+      return nil, false
+    }
+    positions = append(positions, j)
+  }
+  return positions, true
+}
+
+// matchBacktrack matches n glyphs preceding position i, nearest first.
+func (s *shaper) matchBacktrack(i, n int, match matchFunc) bool {
+  j := i
+  for k := 0; k < n; k++ {
+    j = s.prev(j)
+    if j < 0 || !match(k, s.info[j].id) {
+      return false
+    }
+  }
+  return true
+}
+
+// matchLookahead matches n glyphs following position i.
+func (s *shaper) matchLookahead(i, n int, match matchFunc) bool {
+  _, ok := s.matchInput(i, n, match)
+  return ok
+}
+
+// applyRecords applies the nested lookups of matched SequenceLookupRecords.
+func (s *shaper) applyRecords(records []byte, count int, positions []int) {
+  flag, markSet := s.flag, s.markSet
+  for r := 0; r < count; r++ {
+    seqIndex := int(u16(records, 4*r))
+    lookupIndex := int(u16(records, 4*r+2))
+    if seqIndex >= len(positions) || lookupIndex >= len(s.table.lookups) {
+      continue
+    }
+    i := positions[seqIndex]
+    if i >= len(s.info) {
+      continue
+    }
+    before := len(s.info)
+    s.applyLookup(&s.table.lookups[lookupIndex], i)
+    s.flag, s.markSet = flag, markSet
+    // shift the remaining positions when a nested lookup changed the glyph count
+    if delta := len(s.info) - before; delta != 0 {
+      for k := seqIndex + 1; k < len(positions); k++ {
+        positions[k] += delta
+      }
+    }
+  }
+  s.step = 1
+}
+
+// context applies a (GSUB 5 / GPOS 7) contextual subtable at position i.
+func (s *shaper) context(st []byte, i int) bool {
+  g := s.info[i].id
+  switch u16(st, 0) {
+  case 1, 2:
+    format := u16(st, 0)
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 {
+      return false
+    }
+    setCount, sets := int(u16(st, 4)), 6
+    var classDef []byte
+    if format == 2 {
+      classDef = sub(st, int(u16(st, 4)))
+      setCount, sets = int(u16(st, 6)), 8
+      ci = int(classOf(classDef, g))
+    }
+    if ci >= setCount {
+      return false
+    }
+    ruleSet := sub(st, int(u16(st, sets+2*ci)))
+    for k := 0; k < int(u16(ruleSet, 0)); k++ {
+      rule := sub(ruleSet, int(u16(ruleSet, 2+2*k)))
+      glyphCount, lookupCount := int(u16(rule, 0)), int(u16(rule, 2))
+      if glyphCount == 0 {
+        continue
+      }
+      positions, ok := s.matchInput(i, glyphCount-1, func(k int, g truetype.Index) bool {
+        v := u16(rule, 4+2*k)
+        if format == 2 {
+          return classOf(classDef, g) == v
+        }
+        return truetype.Index(v) == g
+      })
+      if !ok {
+        continue
+      }
+      s.applyRecords(sub(rule, 4+2*(glyphCount-1)), lookupCount, positions)
+      return true
+    }
+
+  case 3:
+    glyphCount, lookupCount := int(u16(st, 2)), int(u16(st, 4))
+    if glyphCount == 0 || coverageIndex(sub(st, int(u16(st, 6))), g) < 0 {
+      return false
+    }
+    positions, ok := s.matchInput(i, glyphCount-1, func(k int, g truetype.Index) bool {
+      return coverageIndex(sub(st, int(u16(st, 8+2*k))), g) >= 0
+    })
+    if !ok {
+      return false
+    }
+    s.applyRecords(sub(st, 6+2*glyphCount), lookupCount, positions)
+    return true
+  }
+  return false
+}
+
+// chainContext applies a (GSUB 6 / GPOS 8) chained contextual subtable at position i.
+func (s *shaper) chainContext(st []byte, i int) bool {
+  g := s.info[i].id
+  switch u16(st, 0) {
+  case 1, 2:
+    format := u16(st, 0)
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 {
+      return false
+    }
+    setCount, sets := int(u16(st, 4)), 6
+    var backtrackDef, inputDef, lookaheadDef []byte
+    if format == 2 {
+      backtrackDef = sub(st, int(u16(st, 4)))
+      inputDef = sub(st, int(u16(st, 6)))
+      lookaheadDef = sub(st, int(u16(st, 8)))
+      setCount, sets = int(u16(st, 10)), 12
+      ci = int(classOf(inputDef, g))
+    }
+    if ci >= setCount {
+      return false
+    }
+    matcher := func(seq []byte, classDef []byte) matchFunc {
+      return func(k int, g truetype.Index) bool {
+        v := u16(seq, 2*k)
+        if format == 2 {
+          return classOf(classDef, g) == v
+        }
+        return truetype.Index(v) == g
+      }
+    }
+    ruleSet := sub(st, int(u16(st, sets+2*ci)))
+    for k := 0; k < int(u16(ruleSet, 0)); k++ {
+      rule := sub(ruleSet, int(u16(ruleSet, 2+2*k)))
+      off := 0
+      backtrackCount := int(u16(rule, off))
+      backtrack := sub(rule, off+2)
+      off += 2 + 2*backtrackCount
+      inputCount := int(u16(rule, off))
+      input := sub(rule, off+2)
+      off += 2 + 2*(inputCount-1)
+      lookaheadCount := int(u16(rule, off))
+      lookahead := sub(rule, off+2)
+      off += 2 + 2*lookaheadCount
+      lookupCount := int(u16(rule, off))
+      if inputCount == 0 {
+        continue
+      }
+
+      positions, ok := s.matchInput(i, inputCount-1, matcher(input, inputDef))
+      if !ok ||
+        !s.matchBacktrack(i, backtrackCount, matcher(backtrack, backtrackDef)) ||
+        !s.matchLookahead(positions[len(positions)-1], lookaheadCount, matcher(lookahead, lookaheadDef)) {
+        continue
+      }
+      s.applyRecords(sub(rule, off+2), lookupCount, positions)
+      return true
+    }
+
+  case 3:
+    off := 2
+    backtrackCount := int(u16(st, off))
+    backtrack := off + 2
+    off += 2 + 2*backtrackCount
+    inputCount := int(u16(st, off))
+    input := off + 2
+    off += 2 + 2*inputCount
+    lookaheadCount := int(u16(st, off))
+    lookahead := off + 2
+    off += 2 + 2*lookaheadCount
+    lookupCount := int(u16(st, off))
+    if inputCount == 0 || coverageIndex(sub(st, int(u16(st, input))), g) < 0 {
+      return false
+    }
+    coverage := func(base int) matchFunc {
+      return func(k int, g truetype.Index) bool {
+        return coverageIndex(sub(st, int(u16(st, base+2*k))), g) >= 0
+      }
+    }
+    positions, ok := s.matchInput(i, inputCount-1, coverage(input+2))
+    if !ok ||
+      !s.matchBacktrack(i, backtrackCount, coverage(backtrack)) ||
+      !s.matchLookahead(positions[len(positions)-1], lookaheadCount, coverage(lookahead)) {
+      return false
+    }
+    s.applyRecords(sub(st, off+2), lookupCount, positions)
+    return true
+  }
+  return false
+}
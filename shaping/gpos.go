@@ -0,0 +1,227 @@
+package shaping
+
+import (
+  "github.com/golang/freetype/truetype"
+)
+
+// GPOS lookup types
+const (
+  gposSingle       = 1
+  gposPair         = 2
+  gposCursive      = 3
+  gposMarkToBase   = 4
+  gposMarkToLig    = 5
+  gposMarkToMark   = 6
+  gposContext      = 7
+  gposChainContext = 8
+  gposExtension    = 9
+)
+
+// valueSize returns the size in bytes of a ValueRecord of the given format.
+func valueSize(format uint16) int {
+  n := 0
+  for ; format != 0; format >>= 1 {
+    n += int(format & 1)
+  }
+  return 2 * n
+}
+
+// addValue adds a ValueRecord to a glyph position. Device tables are ignored.
+func addValue(p *glyphPos, format uint16, b []byte, off int) {
+  if format&0x0001 != 0 {
+    p.xOffset += int32(i16(b, off))
+    off += 2
+  }
+  if format&0x0002 != 0 {
+    p.yOffset += int32(i16(b, off))
+    off += 2
+  }
+  if format&0x0004 != 0 {
+    p.xAdvance += int32(i16(b, off))
+    off += 2
+  }
+  if format&0x0008 != 0 {
+    p.yAdvance += int32(i16(b, off))
+  }
+}
+
+// anchor returns the coordinates of an Anchor table, in font units.
+func anchor(b []byte) (int32, int32, bool) {
+  if b == nil {
+    return 0, 0, false
+  }
+  return int32(i16(b, 2)), int32(i16(b, 4)), true
+}
+
+// position applies one GPOS subtable at position i.
+func (s *shaper) position(kind uint16, st []byte, i int) bool {
+  g := s.info[i].id
+  switch kind {
+  case gposSingle:
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 {
+      return false
+    }
+    format := u16(st, 4)
+    switch u16(st, 0) {
+    case 1:
+      addValue(&s.pos[i], format, st, 6)
+    case 2:
+      if ci >= int(u16(st, 6)) {
+        return false
+      }
+      addValue(&s.pos[i], format, st, 8+ci*valueSize(format))
+    default:
+      return false
+    }
+    return true
+
+  case gposPair:
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 {
+      return false
+    }
+    j := s.next(i)
+    if j < 0 {
+      return false
+    }
+    g2 := s.info[j].id
+    format1, format2 := u16(st, 4), u16(st, 6)
+    size1, size2 := valueSize(format1), valueSize(format2)
+    switch u16(st, 0) {
+    case 1:
+      if ci >= int(u16(st, 8)) {
+        return false
+      }
+      pairSet := sub(st, int(u16(st, 10+2*ci)))
+      recSize := 2 + size1 + size2
+      found := false
+      for k := 0; k < int(u16(pairSet, 0)); k++ {
+        rec := 2 + k*recSize
+        if truetype.Index(u16(pairSet, rec)) == g2 {
+          addValue(&s.pos[i], format1, pairSet, rec+2)
+          addValue(&s.pos[j], format2, pairSet, rec+2+size1)
+          found = true
+          break
+        }
+      }
+      if !found {
+        return false
+      }
+    case 2:
+      c1 := int(classOf(sub(st, int(u16(st, 8))), g))
+      c2 := int(classOf(sub(st, int(u16(st, 10))), g2))
+      class1Count, class2Count := int(u16(st, 12)), int(u16(st, 14))
+      if c1 >= class1Count || c2 >= class2Count {
+        return false
+      }
+      rec := 16 + (c1*class2Count+c2)*(size1+size2)
+      addValue(&s.pos[i], format1, st, rec)
+      addValue(&s.pos[j], format2, st, rec+size1)
+    default:
+      return false
+    }
+    if format2 != 0 {
+      s.step = j - i + 1
+    } else {
+      s.step = j - i
+    }
+    return true
+
+  case gposMarkToBase, gposMarkToLig, gposMarkToMark:
+    if u16(st, 0) != 1 {
+      return false
+    }
+    markIndex := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if markIndex < 0 {
+      return false
+    }
+    // find the glyph the mark attaches to
+    j := i - 1
+    if kind == gposMarkToMark {
+      j = s.prev(i)
+      if j < 0 || s.font.glyphClass(s.info[j].id) != classMark {
+        return false
+      }
+    } else {
+      for j >= 0 && s.font.glyphClass(s.info[j].id) == classMark {
+        j--
+      }
+    }
+    if j < 0 {
+      return false
+    }
+    baseIndex := coverageIndex(sub(st, int(u16(st, 4))), s.info[j].id)
+    if baseIndex < 0 {
+      return false
+    }
+    classCount := int(u16(st, 6))
+    markArray := sub(st, int(u16(st, 8)))
+    if markIndex >= int(u16(markArray, 0)) {
+      return false
+    }
+    markClass := int(u16(markArray, 2+4*markIndex))
+    mx, my, ok := anchor(sub(markArray, int(u16(markArray, 2+4*markIndex+2))))
+    if !ok || markClass >= classCount {
+      return false
+    }
+
+    baseArray := sub(st, int(u16(st, 10)))
+    if baseIndex >= int(u16(baseArray, 0)) {
+      return false
+    }
+    var bx, by int32
+    if kind == gposMarkToLig {
+      // attach to the last component; component tracking is not kept
+      ligAttach := sub(baseArray, int(u16(baseArray, 2+2*baseIndex)))
+      components := int(u16(ligAttach, 0))
+      if components == 0 {
+        return false
+      }
+      rec := 2 + ((components-1)*classCount+markClass)*2
+      bx, by, ok = anchor(sub(ligAttach, int(u16(ligAttach, rec))))
+    } else {
+      rec := 2 + (baseIndex*classCount+markClass)*2
+      bx, by, ok = anchor(sub(baseArray, int(u16(baseArray, rec))))
+    }
+    if !ok {
+      return false
+    }
+    s.pos[i].attach = j
+    s.pos[i].xOffset = bx - mx
+    s.pos[i].yOffset = by - my
+    return true
+
+  case gposContext:
+    return s.context(st, i)
+
+  case gposChainContext:
+    return s.chainContext(st, i)
+  }
+  // cursive attachment is not supported
+  return false
+}
+
+// resolveAttachments turns mark offsets relative to their base glyph into
+// offsets relative to the mark's own pen position.
+func (s *shaper) resolveAttachments() {
+  for i := range s.pos {
+    j := s.pos[i].attach
+    if j < 0 || j >= i {
+      continue
+    }
+    var between int32
+    if s.rtl {
+      // glyphs are drawn in reverse order; the pen passes i..j+1 before reaching j
+      for k := j + 1; k <= i; k++ {
+        between += s.pos[k].xAdvance
+      }
+    } else {
+      for k := j; k < i; k++ {
+        between -= s.pos[k].xAdvance
+      }
+    }
+    s.pos[i].xOffset += s.pos[j].xOffset + between
+    s.pos[i].yOffset += s.pos[j].yOffset
+  }
+}
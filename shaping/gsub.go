@@ -0,0 +1,154 @@
+package shaping
+
+import (
+  "github.com/golang/freetype/truetype"
+)
+
+// GSUB lookup types
+const (
+  gsubSingle       = 1
+  gsubMultiple     = 2
+  gsubAlternate    = 3
+  gsubLigature     = 4
+  gsubContext      = 5
+  gsubChainContext = 6
+  gsubExtension    = 7
+)
+
+// substitute applies one GSUB subtable at position i.
+func (s *shaper) substitute(kind uint16, st []byte, i int) bool {
+  g := s.info[i].id
+  switch kind {
+  case gsubSingle:
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 {
+      return false
+    }
+    switch u16(st, 0) {
+    case 1:
+      s.info[i].id = truetype.Index(uint16(g) + u16(st, 4))
+    case 2:
+      if ci >= int(u16(st, 4)) {
+        return false
+      }
+      s.info[i].id = truetype.Index(u16(st, 6+2*ci))
+    default:
+      return false
+    }
+    return true
+
+  case gsubMultiple, gsubAlternate:
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 || ci >= int(u16(st, 4)) {
+      return false
+    }
+    seq := sub(st, int(u16(st, 6+2*ci)))
+    n := int(u16(seq, 0))
+    if n == 0 {
+      return false
+    }
+    if kind == gsubAlternate {
+      // without a way to choose, use the first alternate
+      s.info[i].id = truetype.Index(u16(seq, 2))
+      return true
+    }
+    out := make([]glyphInfo, n)
+    for k := range out {
+      out[k] = s.info[i]
+      out[k].id = truetype.Index(u16(seq, 2+2*k))
+    }
+    s.replace(i, 1, out)
+    s.step = n
+    return true
+
+  case gsubLigature:
+    ci := coverageIndex(sub(st, int(u16(st, 2))), g)
+    if ci < 0 || ci >= int(u16(st, 4)) {
+      return false
+    }
+    ligSet := sub(st, int(u16(st, 6+2*ci)))
+    for k := 0; k < int(u16(ligSet, 0)); k++ {
+      lig := sub(ligSet, int(u16(ligSet, 2+2*k)))
+      count := int(u16(lig, 2))
+      if count == 0 {
+        continue
+      }
+      positions, ok := s.matchInput(i, count-1, func(k int, g truetype.Index) bool {
+        return truetype.Index(u16(lig, 4+2*k)) == g
+      })
+      if !ok {
+        continue
+      }
+      s.info[i].id = truetype.Index(u16(lig, 0))
+      // remove matched components back to front, keeping skipped marks in place
+      for k := len(positions) - 1; k > 0; k-- {
+        s.replace(positions[k], 1, nil)
+      }
+      return true
+    }
+    return false
+
+  case gsubContext:
+    return s.context(st, i)
+
+  case gsubChainContext:
+    return s.chainContext(st, i)
+  }
+  return false
+}
+
+// closure adds the glyphs that GSUB can produce from the glyphs in set.
+// It over-approximates by ignoring contexts, which is fine for baking atlases.
+func (f *Font) closure(set map[truetype.Index]bool) {
+  if f.gsub == nil {
+    return
+  }
+  for changed := true; changed; {
+    changed = false
+    add := func(g truetype.Index) {
+      if !set[g] {
+        set[g] = true
+        changed = true
+      }
+    }
+    for _, l := range f.gsub.lookups {
+      for _, st := range l.subtables {
+        cov := sub(st, int(u16(st, 2)))
+        for g := range set {
+          ci := coverageIndex(cov, g)
+          if ci < 0 {
+            continue
+          }
+          switch l.kind {
+          case gsubSingle:
+            switch u16(st, 0) {
+            case 1:
+              add(truetype.Index(uint16(g) + u16(st, 4)))
+            case 2:
+              if ci < int(u16(st, 4)) {
+                add(truetype.Index(u16(st, 6+2*ci)))
+              }
+            }
+          case gsubMultiple, gsubAlternate:
+            seq := sub(st, int(u16(st, 6+2*ci)))
+            for k := 0; k < int(u16(seq, 0)); k++ {
+              add(truetype.Index(u16(seq, 2+2*k)))
+            }
+          case gsubLigature:
+            ligSet := sub(st, int(u16(st, 6+2*ci)))
+          Ligatures:
+            for k := 0; k < int(u16(ligSet, 0)); k++ {
+              lig := sub(ligSet, int(u16(ligSet, 2+2*k)))
+              for c := 0; c < int(u16(lig, 2))-1; c++ {
+                if !set[truetype.Index(u16(lig, 4+2*c))] {
+                  continue Ligatures
+                }
+              }
+              add(truetype.Index(u16(lig, 0)))
+            }
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,274 @@
+package shaping
+
+import (
+  "fmt"
+  "sort"
+
+  "github.com/golang/freetype/truetype"
+)
+
+// binary readers return zero for out of range reads so that malformed
+// tables degrade to "no match" instead of panicking.
+func u16(b []byte, i int) uint16 {
+  if i < 0 || i+2 > len(b) {
+    return 0
+  }
+  return uint16(b[i])<<8 | uint16(b[i+1])
+}
+
+func i16(b []byte, i int) int16 {
+  return int16(u16(b, i))
+}
+
+func u32(b []byte, i int) uint32 {
+  if i < 0 || i+4 > len(b) {
+    return 0
+  }
+  return uint32(b[i])<<24 | uint32(b[i+1])<<16 | uint32(b[i+2])<<8 | uint32(b[i+3])
+}
+
+// sub returns b from offset off, or nil when off is zero or out of range.
+func sub(b []byte, off int) []byte {
+  if off <= 0 || off >= len(b) {
+    return nil
+  }
+  return b[off:]
+}
+
+// findTable returns the bytes of the sfnt table with the given tag, or nil.
+func findTable(ttf []byte, tag string) ([]byte, error) {
+  if len(ttf) < 12 {
+    return nil, fmt.Errorf("shaping: font data too short")
+  }
+  numTables := int(u16(ttf, 4))
+  for i := 0; i < numTables; i++ {
+    rec := 12 + 16*i
+    if rec+16 > len(ttf) {
+      return nil, fmt.Errorf("shaping: truncated table directory")
+    }
+    if string(ttf[rec:rec+4]) != tag {
+      continue
+    }
+    off, length := int(u32(ttf, rec+8)), int(u32(ttf, rec+12))
+    if off < 0 || length < 0 || off+length > len(ttf) {
+      return nil, fmt.Errorf("shaping: table %s out of bounds", tag)
+    }
+    return ttf[off : off+length], nil
+  }
+  return nil, nil
+}
+
+// coverageIndex returns the coverage index of g in a Coverage table, or -1.
+func coverageIndex(cov []byte, g truetype.Index) int {
+  switch u16(cov, 0) {
+  case 1:
+    n := int(u16(cov, 2))
+    i := sort.Search(n, func(i int) bool {
+      return truetype.Index(u16(cov, 4+2*i)) >= g
+    })
+    if i < n && truetype.Index(u16(cov, 4+2*i)) == g {
+      return i
+    }
+  case 2:
+    n := int(u16(cov, 2))
+    i := sort.Search(n, func(i int) bool {
+      return truetype.Index(u16(cov, 4+6*i+2)) >= g
+    })
+    if i < n {
+      rec := 4 + 6*i
+      start := truetype.Index(u16(cov, rec))
+      if g >= start {
+        return int(u16(cov, rec+4)) + int(g-start)
+      }
+    }
+  }
+  return -1
+}
+
+// classOf returns the class of g in a ClassDef table; unlisted glyphs are class 0.
+func classOf(cd []byte, g truetype.Index) uint16 {
+  switch u16(cd, 0) {
+  case 1:
+    start := truetype.Index(u16(cd, 2))
+    n := truetype.Index(u16(cd, 4))
+    if g >= start && g < start+n {
+      return u16(cd, 6+2*int(g-start))
+    }
+  case 2:
+    n := int(u16(cd, 2))
+    i := sort.Search(n, func(i int) bool {
+      return truetype.Index(u16(cd, 4+6*i+2)) >= g
+    })
+    if i < n {
+      rec := 4 + 6*i
+      if g >= truetype.Index(u16(cd, rec)) {
+        return u16(cd, rec+4)
+      }
+    }
+  }
+  return 0
+}
+
+// lookup flag bits
+const (
+  flagRightToLeft         = 0x0001
+  flagIgnoreBaseGlyphs    = 0x0002
+  flagIgnoreLigatures     = 0x0004
+  flagIgnoreMarks         = 0x0008
+  flagUseMarkFilteringSet = 0x0010
+)
+
+type lookup struct {
+  kind      uint16
+  flag      uint16
+  markSet   uint16
+  subtables [][]byte
+}
+
+type feature struct {
+  tag     string
+  lookups []uint16
+}
+
+type langSys struct {
+  required int
+  features []uint16
+}
+
+// layoutTable is a parsed GSUB or GPOS table.
+type layoutTable struct {
+  scripts  map[string][]byte
+  features []feature
+  lookups  []lookup
+}
+
+// parseLayoutTable parses the common header of GSUB and GPOS. extension is the
+// lookup type of extension subtables, which are resolved here.
+func parseLayoutTable(b []byte, extension uint16) *layoutTable {
+  if b == nil {
+    return nil
+  }
+  t := &layoutTable{scripts: make(map[string][]byte)}
+
+  scriptList := sub(b, int(u16(b, 4)))
+  for i := 0; i < int(u16(scriptList, 0)); i++ {
+    rec := 2 + 6*i
+    if rec+6 > len(scriptList) {
+      break
+    }
+    t.scripts[string(scriptList[rec:rec+4])] = sub(scriptList, int(u16(scriptList, rec+4)))
+  }
+
+  featureList := sub(b, int(u16(b, 6)))
+  for i := 0; i < int(u16(featureList, 0)); i++ {
+    rec := 2 + 6*i
+    if rec+6 > len(featureList) {
+      break
+    }
+    f := feature{tag: string(featureList[rec : rec+4])}
+    ft := sub(featureList, int(u16(featureList, rec+4)))
+    for j := 0; j < int(u16(ft, 2)); j++ {
+      f.lookups = append(f.lookups, u16(ft, 4+2*j))
+    }
+    t.features = append(t.features, f)
+  }
+
+  lookupList := sub(b, int(u16(b, 8)))
+  for i := 0; i < int(u16(lookupList, 0)); i++ {
+    lt := sub(lookupList, int(u16(lookupList, 2+2*i)))
+    l := lookup{kind: u16(lt, 0), flag: u16(lt, 2)}
+    n := int(u16(lt, 4))
+    for j := 0; j < n; j++ {
+      st := sub(lt, int(u16(lt, 6+2*j)))
+      if l.kind == extension && st != nil {
+        l.kind = u16(st, 2)
+        st = sub(st, int(u32(st, 4)))
+      }
+      l.subtables = append(l.subtables, st)
+    }
+    if l.flag&flagUseMarkFilteringSet != 0 {
+      l.markSet = u16(lt, 6+2*n)
+    }
+    t.lookups = append(t.lookups, l)
+  }
+  return t
+}
+
+// langSys selects the language system for a script and language, falling
+// back to the default script and default language system.
+func (t *layoutTable) langSys(script, lang string) (langSys, bool) {
+  s, ok := t.scripts[script]
+  if !ok {
+    if s, ok = t.scripts["DFLT"]; !ok {
+      if s, ok = t.scripts["dflt"]; !ok {
+        if s, ok = t.scripts["latn"]; !ok {
+          return langSys{}, false
+        }
+      }
+    }
+  }
+  ls := sub(s, int(u16(s, 0)))
+  for i := 0; i < int(u16(s, 2)); i++ {
+    rec := 4 + 6*i
+    if rec+6 <= len(s) && string(s[rec:rec+4]) == lang {
+      ls = sub(s, int(u16(s, rec+4)))
+      break
+    }
+  }
+  if ls == nil {
+    return langSys{}, false
+  }
+  sys := langSys{required: -1}
+  if req := u16(ls, 2); req != 0xFFFF {
+    sys.required = int(req)
+  }
+  for i := 0; i < int(u16(ls, 4)); i++ {
+    sys.features = append(sys.features, u16(ls, 6+2*i))
+  }
+  return sys, true
+}
+
+// lookupMasks maps lookup indices to the feature mask bits that enable them.
+// masks gives the bit for each wanted feature tag.
+func (t *layoutTable) lookupMasks(script, lang string, masks map[string]uint32) map[uint16]uint32 {
+  result := make(map[uint16]uint32)
+  if t == nil {
+    return result
+  }
+  sys, ok := t.langSys(script, lang)
+  if !ok {
+    return result
+  }
+  add := func(fi int, mask uint32) {
+    if fi < 0 || fi >= len(t.features) {
+      return
+    }
+    for _, li := range t.features[fi].lookups {
+      if int(li) < len(t.lookups) {
+        result[li] |= mask
+      }
+    }
+  }
+  if sys.required >= 0 {
+    add(sys.required, maskGlobal)
+  }
+  for _, fi := range sys.features {
+    if int(fi) >= len(t.features) {
+      continue
+    }
+    if mask, ok := masks[t.features[fi].tag]; ok {
+      add(int(fi), mask)
+    }
+  }
+  return result
+}
+
+// sortedLookups returns the keys of a lookup mask map in lookup list order.
+func sortedLookups(m map[uint16]uint32) []uint16 {
+  var keys []uint16
+  for k := range m {
+    keys = append(keys, k)
+  }
+  sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+  return keys
+}
@@ -0,0 +1,407 @@
+// Package shaping turns strings into positioned glyphs using the OpenType
+// GSUB and GPOS tables of the font an atlas was built from.
+//
+// Substitutions cover single, multiple, alternate and ligature lookups, plus
+// contextual and chained contextual lookups. Positioning covers single and
+// pair adjustment and mark attachment (mark-to-base, mark-to-ligature and
+// mark-to-mark). Arabic script text gets its isol/init/medi/fina forms from
+// the Unicode joining types. Cursive attachment is not implemented.
+//
+// Shaped glyphs are identified by glyph index; bake them into an atlas with
+// ratlas.Options.Glyphs (see Font.Closure) and resolve them with Atlas.Glyph.
+package shaping
+
+import (
+  "fmt"
+  "sort"
+  "unicode"
+
+  "github.com/golang/freetype/truetype"
+  "golang.org/x/image/math/fixed"
+)
+
+// Direction is the direction a run of text is written in.
+type Direction int
+
+const (
+  // Auto picks the direction from the script of the first strong rune.
+  Auto Direction = iota
+  LeftToRight
+  RightToLeft
+)
+
+// Options configures Shape.
+type Options struct {
+  // Script and Language are OpenType tags such as "arab" and "FAR ".
+  // An empty Script is guessed from the text; an empty Language uses the
+  // script's default language system.
+  Script   string
+  Language string
+
+  Direction Direction
+
+  // Features turns optional OpenType features on (true) or default ones off (false).
+  Features map[string]bool
+}
+
+// Glyph is a shaped glyph. Values are in pixels at the size passed to Shape;
+// YAdvance and YOffset grow upwards as in the font.
+type Glyph struct {
+  ID       truetype.Index
+  Cluster  int
+  XAdvance float32
+  YAdvance float32
+  XOffset  float32
+  YOffset  float32
+}
+
+// GDEF glyph classes
+const (
+  classBase      = 1
+  classLigature  = 2
+  classMark      = 3
+  classComponent = 4
+)
+
+// Font holds a parsed font and its OpenType layout tables.
+type Font struct {
+  font *truetype.Font
+
+  gsub *layoutTable
+  gpos *layoutTable
+
+  glyphClassDef []byte
+  markAttachDef []byte
+  markGlyphSets []byte
+}
+
+// Parse parses TTF data for shaping. Fonts without GSUB or GPOS tables are
+// accepted and shape with their cmap, advances and kern table only.
+func Parse(ttfData []byte) (*Font, error) {
+  f, err := truetype.Parse(ttfData)
+  if err != nil {
+    return nil, fmt.Errorf("shaping: couldn't parse font: %v", err)
+  }
+  font := &Font{font: f}
+
+  gsub, err := findTable(ttfData, "GSUB")
+  if err != nil {
+    return nil, err
+  }
+  gpos, err := findTable(ttfData, "GPOS")
+  if err != nil {
+    return nil, err
+  }
+  gdef, err := findTable(ttfData, "GDEF")
+  if err != nil {
+    return nil, err
+  }
+  font.gsub = parseLayoutTable(gsub, gsubExtension)
+  font.gpos = parseLayoutTable(gpos, gposExtension)
+  if gdef != nil {
+    font.glyphClassDef = sub(gdef, int(u16(gdef, 4)))
+    font.markAttachDef = sub(gdef, int(u16(gdef, 10)))
+    if u16(gdef, 2) >= 2 {
+      font.markGlyphSets = sub(gdef, int(u16(gdef, 12)))
+    }
+  }
+  return font, nil
+}
+
+func (f *Font) glyphClass(g truetype.Index) uint16 {
+  return classOf(f.glyphClassDef, g)
+}
+
+// inMarkSet reports whether g is in the given GDEF mark glyph set.
+func (f *Font) inMarkSet(set uint16, g truetype.Index) bool {
+  if int(set) >= int(u16(f.markGlyphSets, 2)) {
+    return false
+  }
+  return coverageIndex(sub(f.markGlyphSets, int(u32(f.markGlyphSets, 4+4*int(set)))), g) >= 0
+}
+
+// ignored reports whether a lookup with the given flag skips glyph g.
+func (f *Font) ignored(g truetype.Index, flag, markSet uint16) bool {
+  switch f.glyphClass(g) {
+  case classBase:
+    return flag&flagIgnoreBaseGlyphs != 0
+  case classLigature:
+    return flag&flagIgnoreLigatures != 0
+  case classMark:
+    if flag&flagIgnoreMarks != 0 {
+      return true
+    }
+    if flag&flagUseMarkFilteringSet != 0 {
+      return !f.inMarkSet(markSet, g)
+    }
+    if attachType := flag >> 8; attachType != 0 {
+      return classOf(f.markAttachDef, g) != attachType
+    }
+  }
+  return false
+}
+
+// Closure returns the glyph indices needed to draw any shaping of the given
+// runes, sorted. Pass it as ratlas.Options.Glyphs when building the atlas.
+func (f *Font) Closure(runes []rune) []truetype.Index {
+  set := make(map[truetype.Index]bool)
+  for _, r := range runes {
+    set[f.font.Index(r)] = true
+  }
+  f.closure(set)
+  var glyphs []truetype.Index
+  for g := range set {
+    glyphs = append(glyphs, g)
+  }
+  sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+  return glyphs
+}
+
+// feature mask bits
+const (
+  maskGlobal uint32 = 1 << iota
+  maskIsol
+  maskFina
+  maskMedi
+  maskInit
+  maskUser
+)
+
+var formMasks = map[form]uint32{
+  formIsol: maskIsol,
+  formFina: maskFina,
+  formMedi: maskMedi,
+  formInit: maskInit,
+}
+
+var (
+  defaultSubstitutions = []string{"ccmp", "locl", "rlig", "calt", "liga", "clig"}
+  arabicSubstitutions  = map[string]uint32{"isol": maskIsol, "fina": maskFina, "medi": maskMedi, "init": maskInit}
+  defaultPositionings  = []string{"kern", "mark", "mkmk"}
+)
+
+type glyphInfo struct {
+  id      truetype.Index
+  cluster int
+  mask    uint32
+}
+
+type glyphPos struct {
+  xAdvance, yAdvance int32
+  xOffset, yOffset   int32
+  attach             int
+}
+
+// shaper holds the glyph buffer while a lookup list is applied.
+type shaper struct {
+  font  *Font
+  table *layoutTable
+  gsub  bool
+  rtl   bool
+
+  info []glyphInfo
+  pos  []glyphPos
+
+  flag    uint16
+  markSet uint16
+  step    int
+}
+
+// next returns the index of the next glyph after i not skipped by the current lookup, or -1.
+func (s *shaper) next(i int) int {
+  for i++; i < len(s.info); i++ {
+    if !s.font.ignored(s.info[i].id, s.flag, s.markSet) {
+      return i
+    }
+  }
+  return -1
+}
+
+// prev returns the index of the previous glyph before i not skipped by the current lookup, or -1.
+func (s *shaper) prev(i int) int {
+  for i--; i >= 0; i-- {
+    if !s.font.ignored(s.info[i].id, s.flag, s.markSet) {
+      return i
+    }
+  }
+  return -1
+}
+
+// replace replaces n glyphs at i with out.
+func (s *shaper) replace(i, n int, out []glyphInfo) {
+  tail := append([]glyphInfo(nil), s.info[i+n:]...)
+  s.info = append(append(s.info[:i], out...), tail...)
+}
+
+// applyLookup tries each subtable of l at position i.
+func (s *shaper) applyLookup(l *lookup, i int) bool {
+  s.flag, s.markSet = l.flag, l.markSet
+  if s.font.ignored(s.info[i].id, s.flag, s.markSet) {
+    return false
+  }
+  for _, st := range l.subtables {
+    if st == nil {
+      continue
+    }
+    var applied bool
+    if s.gsub {
+      applied = s.substitute(l.kind, st, i)
+    } else {
+      applied = s.position(l.kind, st, i)
+    }
+    if applied {
+      return true
+    }
+  }
+  return false
+}
+
+// apply runs the lookups of table that are enabled by masks, in lookup list order.
+func (s *shaper) apply(table *layoutTable, gsub bool, lookupMasks map[uint16]uint32) {
+  if table == nil {
+    return
+  }
+  s.table, s.gsub = table, gsub
+  for _, li := range sortedLookups(lookupMasks) {
+    mask := lookupMasks[li]
+    l := &table.lookups[li]
+    for i := 0; i < len(s.info); {
+      s.step = 1
+      if s.info[i].mask&mask != 0 {
+        s.applyLookup(l, i)
+      }
+      if s.step < 1 {
+        s.step = 1
+      }
+      i += s.step
+    }
+  }
+}
+
+// guessScript returns the OpenType script tag and direction of the first strong rune.
+func guessScript(runes []rune) (string, Direction) {
+  for _, r := range runes {
+    switch {
+    case unicode.Is(unicode.Arabic, r):
+      return "arab", RightToLeft
+    case unicode.Is(unicode.Hebrew, r):
+      return "hebr", RightToLeft
+    case unicode.Is(unicode.Cyrillic, r):
+      return "cyrl", LeftToRight
+    case unicode.Is(unicode.Greek, r):
+      return "grek", LeftToRight
+    case unicode.Is(unicode.Latin, r):
+      return "latn", LeftToRight
+    }
+  }
+  return "DFLT", LeftToRight
+}
+
+// Shape shapes text at the given size in pixels per em, such as Atlas.PixelsPerEm().
+// Glyphs are returned in visual order, left to right; Cluster is the index of
+// the first rune of the glyph's cluster in []rune(text).
+func (f *Font) Shape(text string, size float64, opts Options) []Glyph {
+  runes := []rune(text)
+  script, dir := guessScript(runes)
+  if opts.Script != "" {
+    script = opts.Script
+  }
+  if opts.Direction != Auto {
+    dir = opts.Direction
+  }
+  lang := opts.Language
+  if lang == "" {
+    lang = "dflt"
+  }
+
+  s := &shaper{font: f, rtl: dir == RightToLeft}
+  for i, r := range runes {
+    s.info = append(s.info, glyphInfo{id: f.font.Index(r), cluster: i, mask: maskGlobal})
+  }
+
+  // substitution features
+  masks := make(map[string]uint32)
+  for _, tag := range defaultSubstitutions {
+    masks[tag] = maskGlobal
+  }
+  if script == "arab" {
+    for i, fm := range arabicForms(runes) {
+      s.info[i].mask |= formMasks[fm]
+    }
+    for tag, mask := range arabicSubstitutions {
+      masks[tag] = mask
+    }
+  }
+  userBit := maskUser
+  for tag, on := range opts.Features {
+    if !on {
+      delete(masks, tag)
+      continue
+    }
+    if _, ok := masks[tag]; !ok {
+      masks[tag] = userBit
+      userBit <<= 1
+    }
+  }
+  for i := range s.info {
+    s.info[i].mask |= userBit - maskUser
+  }
+  s.apply(f.gsub, true, f.gsub.lookupMasks(script, lang, masks))
+
+  // marks belong to the cluster of the glyph they follow
+  for i := 1; i < len(s.info); i++ {
+    if f.glyphClass(s.info[i].id) == classMark {
+      s.info[i].cluster = s.info[i-1].cluster
+    }
+  }
+
+  // advances, with marks zeroed so attachment can place them
+  upem := fixed.Int26_6(f.font.FUnitsPerEm())
+  s.pos = make([]glyphPos, len(s.info))
+  for i, gi := range s.info {
+    s.pos[i].attach = -1
+    if f.glyphClass(gi.id) != classMark {
+      s.pos[i].xAdvance = int32(f.font.HMetric(upem, gi.id).AdvanceWidth)
+    }
+  }
+
+  // positioning features
+  masks = make(map[string]uint32)
+  for _, tag := range defaultPositionings {
+    masks[tag] = maskGlobal
+  }
+  for tag, on := range opts.Features {
+    if !on {
+      delete(masks, tag)
+    }
+  }
+  if f.gpos != nil {
+    s.apply(f.gpos, false, f.gpos.lookupMasks(script, lang, masks))
+  } else if _, ok := masks["kern"]; ok {
+    // fall back to the legacy kern table
+    for i := 0; i+1 < len(s.info); i++ {
+      s.pos[i].xAdvance += int32(f.font.Kern(upem, s.info[i].id, s.info[i+1].id))
+    }
+  }
+  s.resolveAttachments()
+
+  scale := float32(size) / float32(upem)
+  glyphs := make([]Glyph, len(s.info))
+  for i, gi := range s.info {
+    p := s.pos[i]
+    glyphs[i] = Glyph{
+      ID:       gi.id,
+      Cluster:  gi.cluster,
+      XAdvance: float32(p.xAdvance) * scale,
+      YAdvance: float32(p.yAdvance) * scale,
+      XOffset:  float32(p.xOffset) * scale,
+      YOffset:  float32(p.yOffset) * scale,
+    }
+  }
+  if s.rtl {
+    for i, j := 0, len(glyphs)-1; i < j; i, j = i+1, j-1 {
+      glyphs[i], glyphs[j] = glyphs[j], glyphs[i]
+    }
+  }
+  return glyphs
+}
@@ -0,0 +1,97 @@
+package shaping
+
+import (
+  "io/ioutil"
+  "math"
+  "testing"
+
+  "github.com/golang/freetype/truetype"
+)
+
+func loadFont(t *testing.T, fileName string) *Font {
+  data, err := ioutil.ReadFile(fileName)
+  if err != nil {
+    t.Fatal(err)
+  }
+  f, err := Parse(data)
+  if err != nil {
+    t.Fatal(err)
+  }
+  return f
+}
+
+func near(a, b float32) bool {
+  return math.Abs(float64(a-b)) < 0.01
+}
+
+// TestShapeGolden checks shaped glyphs against HarfBuzz's output for the same
+// text, at a size of one pixel per font unit. Roboto is under the Apache
+// License and Amiri under the SIL Open Font License; see testdata.
+func TestShapeGolden(t *testing.T) {
+  type glyph struct {
+    id              truetype.Index
+    cluster         int
+    advance, dx, dy float32
+  }
+  for _, test := range []struct {
+    font string
+    size float64
+    text string
+    want []glyph
+  }{
+    // the "ffi" ligature, and kerning between the capitals
+    {"testdata/Roboto-Regular.ttf", 2048, "office AVATAR", []glyph{
+      {83, 0, 1168, 0, 0},
+      {446, 1, 1748, 0, 0},
+      {71, 4, 1072, 0, 0},
+      {73, 5, 1085, 0, 0},
+      {4, 6, 507, 0, 0},
+      {37, 7, 1249, 0, 0},
+      {58, 8, 1228, 0, 0},
+      {37, 9, 1207, 0, 0},
+      {56, 10, 1143, 0, 0},
+      {37, 11, 1336, 0, 0},
+      {54, 12, 1261, 0, 0},
+    }},
+    {"testdata/Roboto-Regular.ttf", 2048, "fi", []glyph{
+      {444, 0, 1134, 0, 0},
+    }},
+    // final, medial and initial forms, each carrying a mark attached to it,
+    // in visual order
+    {"testdata/Amiri-Regular.ttf", 1000, "بِسْمِ", []glyph{
+      {432, 4, 0, 76, 0},
+      {4540, 4, 565, 0, 0},
+      {434, 2, 0, -175, 0},
+      {4170, 2, 291, 0, 0},
+      {432, 0, 0, -133, 0},
+      {4147, 0, 219, 0, 0},
+    }},
+    // marks moved down as well as across, and a kerned base
+    {"testdata/Amiri-Regular.ttf", 1000, "عَرَبِيّ", []glyph{
+      {433, 6, 0, -13, 0},
+      {5139, 6, 476, 0, 0},
+      {432, 4, 0, -94, -64},
+      {5056, 4, 258, 0, 0},
+      {430, 2, 0, 71, -464},
+      {3111, 2, 561, 88, 0},
+      {430, 0, 0, -76, 0},
+      {2049, 0, 477, 0, 0},
+    }},
+    // the isolated form
+    {"testdata/Amiri-Regular.ttf", 1000, "ب", []glyph{
+      {392, 0, 926, 0, 0},
+    }},
+  } {
+    got := loadFont(t, test.font).Shape(test.text, test.size, Options{})
+    if len(got) != len(test.want) {
+      t.Errorf("%q: %d glyphs, want %d: %v", test.text, len(got), len(test.want), got)
+      continue
+    }
+    for i, g := range got {
+      w := test.want[i]
+      if g.ID != w.id || g.Cluster != w.cluster || !near(g.XAdvance, w.advance) || !near(g.XOffset, w.dx) || !near(g.YOffset, w.dy) || g.YAdvance != 0 {
+        t.Errorf("%q: glyph %d is %+v, want %+v", test.text, i, g, w)
+      }
+    }
+  }
+}
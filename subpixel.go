@@ -0,0 +1,65 @@
+package ratlas
+
+import (
+  "fmt"
+  "math"
+
+  "golang.org/x/image/math/fixed"
+)
+
+// Variant returns the variant of an item to draw with its origin at x: the
+// one whose subpixel phase is nearest the fraction of x, or the item itself
+// if it has no variants. Drawn at x, the variant's quad starts within 1/2N
+// pixels of a whole pixel.
+func (atlasItem *AtlasItem) Variant(x float32) *AtlasItem {
+  n := len(atlasItem.Variants)
+  if n == 0 {
+    return atlasItem
+  }
+  frac := x - float32(math.Floor(float64(x)))
+  return atlasItem.Variants[int(frac*float32(n)+0.5)%n]
+}
+
+// addVariants adds n-1 subpixel variants, for phases 1/n and up, to every
+// item; finishVariants adds phase 0 once items are packed.
+func (atlas *Atlas) addVariants(n, pad, imgWidth, imgHeight int) {
+  if n < 2 {
+    return
+  }
+  for _, atlasItem := range atlas.allItems() {
+    bounds, advance, err := atlas.glyphBounds(atlasItem.Glyph)
+    if err != nil {
+      fmt.Println("ratlas: no variants for glyph", atlasItem.Glyph, err)
+      continue
+    }
+    atlasItem.Variants = make([]*AtlasItem, n)
+    for k := 1; k < n; k++ {
+      phase := float32(k) / float32(n)
+      shifted := bounds
+      shifted.Min.X += fixed.Int26_6(phase * 64)
+      shifted.Max.X += fixed.Int26_6(phase * 64)
+      
+      variant := &AtlasItem{Rune: atlasItem.Rune, Glyph: atlasItem.Glyph, phase: phase}
+      setMetrics(variant, shifted, advance, pad, imgWidth, imgHeight)
+      // relative to the unshifted origin, landing the image on a whole pixel
+      variant.BearingX = float32(shifted.Min.X.Floor()-pad) - phase
+      atlasItem.Variants[k] = variant
+    }
+  }
+}
+
+// finishVariants makes phase 0 variants, sharing the image of their item.
+func (atlas *Atlas) finishVariants() {
+  for _, atlasItem := range atlas.allItems() {
+    if len(atlasItem.Variants) == 0 || atlasItem.Variants[1] == nil {
+      continue
+    }
+    variant := *atlasItem
+    variant.Variants = nil
+    node := *atlasItem.Node
+    variant.Node = &node
+    // the item's bearing isn't whole, as its image is
+    variant.BearingX = float32(int(math.Floor(float64(variant.BearingX+float32(atlas.Pad)))) - atlas.Pad)
+    atlasItem.Variants[0] = &variant
+  }
+}
@@ -0,0 +1,69 @@
+package ratlas
+
+import (
+  "math"
+  "testing"
+)
+
+// inkCenter returns the coverage weighted x of an item's ink, in texels of
+// its image.
+func inkCenter(t *testing.T, atlas *Atlas, item *AtlasItem) float64 {
+  sums, centers := rowInk(t, atlas, item)
+  var sum, x float64
+  for y, s := range sums {
+    sum += s
+    x += s * centers[y]
+  }
+  return x/sum
+}
+
+// TestVariant checks that Variant picks the variant of the nearest phase,
+// so quads start close to whole pixels, and that each variant's texels are
+// the glyph shifted by its phase, which its bearing takes back.
+func TestVariant(t *testing.T) {
+  data := testFont(t)
+  const n = 4
+  atlas := NewWithOptions(&data, []rune("oAl"), Options{FontPt: 24, Width: 128, Height: 128, Pad: 2, SubpixelVariants: n})
+  item := atlas.Items['o']
+  if len(item.Variants) != n {
+    t.Fatalf("%d variants, want %d", len(item.Variants), n)
+  }
+  for _, test := range []struct {
+    x       float32
+    variant int
+  }{
+    {10, 0}, {10.1, 0}, {10.2, 1}, {10.3, 1}, {10.4, 2}, {10.6, 2}, {10.7, 3}, {10.9, 0}, {-0.25, 3}, {-0.9, 0},
+  } {
+    if got := item.Variant(test.x); got != item.Variants[test.variant] {
+      t.Errorf("Variant(%v) isn't variant %d", test.x, test.variant)
+    }
+  }
+  for x := float32(0); x < 3; x += 1.0 / 64 {
+    start := float64(x + item.Variant(x).BearingX)
+    if d := math.Abs(start - math.Floor(start+0.5)); d > 1.0/(2*n)+1e-4 {
+      t.Errorf("at %v the quad starts %v from a whole pixel", x, d)
+    }
+  }
+  if plain := NewWithOptions(&data, []rune("o"), Options{FontPt: 24, Width: 128, Height: 128, Pad: 2}); plain.Items['o'].Variant(1.5) != plain.Items['o'] {
+    t.Errorf("an item without variants doesn't draw itself")
+  }
+
+  for _, r := range "oAl" {
+    item := atlas.Items[r]
+    base := item.Variants[0]
+    for k, variant := range item.Variants {
+      if variant.Node == nil || variant.Advance != item.Advance {
+        t.Fatalf("%q: variant %d isn't packed like its item", r, k)
+      }
+      phase := float64(k) / n
+      shift := inkCenter(t, &atlas, variant) - inkCenter(t, &atlas, base)
+      if d := shift - phase; math.Abs(d-math.Floor(d+0.5)) > 0.05 {
+        t.Errorf("%q: variant %d texels are shifted by %.3f, want %v and whole pixels", r, k, shift, phase)
+      }
+      // drawn from the same origin, ink lands in the same place
+      if moved := shift + float64(variant.BearingX-base.BearingX); math.Abs(moved) > 0.05 {
+        t.Errorf("%q: variant %d moves ink by %.3f", r, k, moved)
+      }
+    }
+  }
+}
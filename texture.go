@@ -0,0 +1,324 @@
+package ratlas
+
+import (
+  "encoding/binary"
+  "fmt"
+  "image"
+  "image/color"
+  "image/draw"
+  "io/ioutil"
+)
+
+// TextureFormat is the texel format of pages saved to GPU texture containers.
+type TextureFormat int
+
+const (
+  // TextureR8 holds the red channel, coverage or fill, in 8 bits.
+  TextureR8 TextureFormat = iota
+
+  // TextureRG8 holds the red and green channels, such as fill and outline
+  // of effects stored in channels.
+  TextureRG8
+
+  // TextureRGBA8 holds every channel, as for LCD pages.
+  TextureRGBA8
+
+  // TextureR16 holds the red channel in 16 bits, for FormatGray16 pages.
+  TextureR16
+
+  // TextureBC4 block compresses the red channel to half a byte per texel.
+  TextureBC4
+
+  // TextureBC5 block compresses the red and green channels to a byte per
+  // texel.
+  TextureBC5
+
+  // TextureEACR11 block compresses the red channel to half a byte per texel
+  // at 11 bits of precision, for OpenGL ES and mobile GPUs. DDS files can't
+  // hold it.
+  TextureEACR11
+)
+
+// TextureOptions configures saving pages to KTX2 and DDS files.
+type TextureOptions struct {
+  Format TextureFormat
+
+  // Mipmaps adds the mip chain of each page, as Atlas.MipChain makes it.
+  Mipmaps bool
+
+  // Array saves all pages as the layers of one array texture in a single
+  // file, rather than a file per page.
+  Array bool
+}
+
+// channels returns the number of channels in the format.
+func (format TextureFormat) channels() int {
+  switch format {
+  case TextureRG8, TextureBC5:
+    return 2
+  case TextureRGBA8:
+    return 4
+  }
+  return 1
+}
+
+// texelSize returns the size of a texel in bytes, of uncompressed formats.
+func (format TextureFormat) texelSize() int {
+  if format == TextureR16 {
+    return 2
+  }
+  return format.channels()
+}
+
+// levelSize returns the size in bytes of a w by h image in the format.
+func (format TextureFormat) levelSize(w, h int) int {
+  if size := format.blockSize(); size > 0 {
+    return (w + 3) / 4 * ((h + 3) / 4) * size
+  }
+  return w * h * format.texelSize()
+}
+
+// textureFormats lists the formats texture files are loaded in.
+var textureFormats = []TextureFormat{TextureR8, TextureRG8, TextureRGBA8, TextureR16, TextureBC4, TextureBC5, TextureEACR11}
+
+// plane is an image of n channels of 16 bit values, which mips are made and
+// texture formats encoded from.
+type plane struct {
+  w, h, n int
+  pix []uint16
+}
+
+func newPlane(w, h, n int) *plane {
+  return &plane{w: w, h: h, n: n, pix: make([]uint16, w*h*n)}
+}
+
+// texels returns the channels of img at (x, y) as stored, without
+// premultiplying them, as they may hold unrelated layers.
+func texels(img image.Image, x, y int) [4]uint16 {
+  switch img := img.(type) {
+  case *image.Gray:
+    v := uint16(img.GrayAt(x, y).Y) * 0x101
+    return [4]uint16{v, v, v, 0xFFFF}
+  case *image.Gray16:
+    v := img.Gray16At(x, y).Y
+    return [4]uint16{v, v, v, 0xFFFF}
+  case *image.Alpha:
+    // coverage is alpha, whichever channel is asked for
+    v := uint16(img.AlphaAt(x, y).A) * 0x101
+    return [4]uint16{v, v, v, v}
+  case *image.NRGBA:
+    p := img.Pix[img.PixOffset(x, y):]
+    return [4]uint16{uint16(p[0]) * 0x101, uint16(p[1]) * 0x101, uint16(p[2]) * 0x101, uint16(p[3]) * 0x101}
+  case *image.RGBA:
+    p := img.Pix[img.PixOffset(x, y):]
+    return [4]uint16{uint16(p[0]) * 0x101, uint16(p[1]) * 0x101, uint16(p[2]) * 0x101, uint16(p[3]) * 0x101}
+  }
+  c := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64)
+  return [4]uint16{c.R, c.G, c.B, c.A}
+}
+
+// pagePlane returns the first n channels of a page.
+func pagePlane(img image.Image, n int) *plane {
+  b := img.Bounds()
+  p := newPlane(b.Dx(), b.Dy(), n)
+  for y := 0; y < p.h; y++ {
+    for x := 0; x < p.w; x++ {
+      t := texels(img, b.Min.X+x, b.Min.Y+y)
+      copy(p.pix[(y*p.w+x)*n:], t[:n])
+    }
+  }
+  return p
+}
+
+// half returns the next mip level of p, averaging 2x2 blocks of texels.
+func (p *plane) half() *plane {
+  w, h := max1(p.w/2), max1(p.h/2)
+  dst := newPlane(w, h, p.n)
+  at := func(x, y, c int) uint32 {
+    if x >= p.w {
+      x = p.w - 1
+    }
+    if y >= p.h {
+      y = p.h - 1
+    }
+    return uint32(p.pix[(y*p.w+x)*p.n+c])
+  }
+  for y := 0; y < h; y++ {
+    for x := 0; x < w; x++ {
+      for c := 0; c < p.n; c++ {
+        sum := at(2*x, 2*y, c) + at(2*x+1, 2*y, c) + at(2*x, 2*y+1, c) + at(2*x+1, 2*y+1, c)
+        dst.pix[(y*w+x)*p.n+c] = uint16((sum + 2) / 4)
+      }
+    }
+  }
+  return dst
+}
+
+// mipChain returns p followed by its mip levels down to 1x1.
+func (p *plane) mipChain() []*plane {
+  chain := []*plane{p}
+  for p.w > 1 || p.h > 1 {
+    p = p.half()
+    chain = append(chain, p)
+  }
+  return chain
+}
+
+// encode returns the texels of p in the format.
+func (format TextureFormat) encode(p *plane) []byte {
+  if format.blockSize() > 0 {
+    return format.compress(p)
+  }
+  b := make([]byte, format.levelSize(p.w, p.h))
+  if format == TextureR16 {
+    for i, v := range p.pix {
+      binary.LittleEndian.PutUint16(b[2*i:], v)
+    }
+    return b
+  }
+  for i, v := range p.pix {
+    b[i] = uint8((uint32(v)*0xFF + 0x7FFF) / 0xFFFF)
+  }
+  return b
+}
+
+// decode returns a w by h image in the format held by b.
+func (format TextureFormat) decode(b []byte, w, h int) *plane {
+  if format.blockSize() > 0 {
+    return format.decompress(b, w, h)
+  }
+  p := newPlane(w, h, format.channels())
+  if format == TextureR16 {
+    for i := range p.pix {
+      p.pix[i] = binary.LittleEndian.Uint16(b[2*i:])
+    }
+    return p
+  }
+  for i := range p.pix {
+    p.pix[i] = uint16(b[i]) * 0x101
+  }
+  return p
+}
+
+// textureLayers returns the mip chain of each page in the format, largest
+// level first.
+func (atlas *Atlas) textureLayers(opts TextureOptions) ([][][]byte, error) {
+  if len(atlas.Images) == 0 {
+    return nil, fmt.Errorf("ratlas: no pages")
+  }
+  size := atlas.Images[0].Bounds().Size()
+  var layers [][][]byte
+  for i, img := range atlas.Images {
+    if opts.Array && img.Bounds().Size() != size {
+      return nil, fmt.Errorf("ratlas: page %d is %v, not %v", i, img.Bounds().Size(), size)
+    }
+    chain := []*plane{pagePlane(img, opts.Format.channels())}
+    if opts.Mipmaps {
+      chain = atlas.mipChain(i, chain[0])
+    }
+    var levels [][]byte
+    for _, p := range chain {
+      levels = append(levels, opts.Format.encode(p))
+    }
+    layers = append(layers, levels)
+  }
+  return layers, nil
+}
+
+// loadTextureLayers appends pages decoded from the top level of each layer
+// of texture files, converting coverage pages to the atlas' Format.
+func (atlas *Atlas) loadTextureLayers(format TextureFormat, layers [][]byte, w, h, total int) {
+  for _, b := range layers {
+    p := format.decode(b, w, h)
+    r := image.Rect(0, 0, w, h)
+    var page draw.Image
+    if atlas.coveragePage(len(atlas.Images), total) {
+      page = atlas.Format.newPage(r)
+      for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+          setCoverage(page, x, y, p.pix[(y*w+x)*p.n])
+        }
+      }
+    } else {
+      nrgba := image.NewNRGBA(r)
+      for i := 0; i < w*h; i++ {
+        for c := 0; c < p.n; c++ {
+          nrgba.Pix[4*i+c] = uint8(p.pix[i*p.n+c] >> 8)
+        }
+      }
+      page = nrgba
+    }
+    atlas.Images = append(atlas.Images, page)
+  }
+}
+
+// setCoverage sets a coverage page's pixel at (x, y) to the 16 bit coverage v.
+func setCoverage(page draw.Image, x, y int, v uint16) {
+  switch page := page.(type) {
+  case *image.Gray:
+    page.SetGray(x, y, color.Gray{uint8(v >> 8)})
+  case *image.Gray16:
+    page.SetGray16(x, y, color.Gray16{v})
+  case *image.Alpha:
+    page.SetAlpha(x, y, color.Alpha{uint8(v >> 8)})
+  case *image.RGBA:
+    c := uint8(v >> 8)
+    page.SetRGBA(x, y, color.RGBA{c, c, c, c})
+  }
+}
+
+// saveTextureFiles saves pages to files of an extension, each encoded with
+// the mip chains of its layers, of the size of the top level.
+func (atlas *Atlas) saveTextureFiles(name, ext string, opts TextureOptions, encode func(layers [][][]byte, w, h int, array bool) []byte) error {
+  layers, err := atlas.textureLayers(opts)
+  if err != nil {
+    return err
+  }
+  save := func(fileName string, layers [][][]byte, size image.Point) error {
+    err := ioutil.WriteFile(fileName, encode(layers, size.X, size.Y, opts.Array), 0644)
+    if err != nil {
+      return fmt.Errorf("ratlas: couldn't write file %s: %v", fileName, err)
+    }
+    fmt.Println("ratlas: wrote", fileName)
+    return nil
+  }
+  if opts.Array {
+    return save(name+ext, layers, atlas.Images[0].Bounds().Size())
+  }
+  for i, img := range atlas.Images {
+    if err := save(fmt.Sprintf("%s-%d%s", name, i, ext), layers[i:i+1], img.Bounds().Size()); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// textureFile is the top level of each layer of a texture file.
+type textureFile struct {
+  format TextureFormat
+  w, h int
+  layers [][]byte
+}
+
+// loadTextureFiles appends the layers of files decoded by decode as pages.
+func (atlas *Atlas) loadTextureFiles(fileNames []string, decode func(b []byte) (*textureFile, error)) error {
+  var files []*textureFile
+  total := len(atlas.Images)
+  for _, fileName := range fileNames {
+    b, err := ioutil.ReadFile(fileName)
+    if err != nil {
+      return fmt.Errorf("ratlas: couldn't read file %s: %v", fileName, err)
+    }
+    file, err := decode(b)
+    if err != nil {
+      return fmt.Errorf("ratlas: couldn't decode %s: %v", fileName, err)
+    }
+    files = append(files, file)
+    total += len(file.layers)
+  }
+  for i, file := range files {
+    atlas.loadTextureLayers(file.format, file.layers, file.w, file.h, total)
+    fmt.Println("ratlas: loaded", fileNames[i])
+  }
+  return nil
+}
@@ -0,0 +1,157 @@
+package ratlas
+
+import (
+  "encoding/binary"
+  "fmt"
+  "io/ioutil"
+  "math"
+  "path/filepath"
+  "testing"
+)
+
+// textureTestAtlas returns a mipmapped atlas of several pages, with an outline
+// in the green channel if channels is set.
+func textureTestAtlas(t *testing.T, channels bool) *Atlas {
+  data, err := ioutil.ReadFile("example/Vera.ttf")
+  if err != nil {
+    t.Fatal(err)
+  }
+  opts := Options{FontPt: 24, Width: 128, Height: 64, Pad: 4, MipLevels: 3}
+  if channels {
+    opts.Effects = Effects{OutlineWidth: 2, Storage: EffectChannels}
+  }
+  atlas := NewWithOptions(&data, []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"), opts)
+  if len(atlas.Images) < 2 {
+    t.Fatalf("%d pages, want several", len(atlas.Images))
+  }
+  return &atlas
+}
+
+// fileLevels returns the levels of each layer of a texture file, as
+// SaveKTX2Files and SaveDDSFiles lay them out.
+func fileLevels(t *testing.T, b []byte, format TextureFormat, layers int, ktx2 bool) [][][]byte {
+  le := binary.LittleEndian
+  var w, h, levels int
+  if ktx2 {
+    w, h, levels = int(le.Uint32(b[20:])), int(le.Uint32(b[24:])), int(le.Uint32(b[40:]))
+  } else {
+    h, w, levels = int(le.Uint32(b[12:])), int(le.Uint32(b[16:])), int(le.Uint32(b[28:]))
+  }
+  out := make([][][]byte, layers)
+  offset := 148
+  for i := range out {
+    for l, lw, lh := 0, w, h; l < levels; l++ {
+      size := format.levelSize(lw, lh)
+      if ktx2 {
+        offset = int(le.Uint64(b[80+24*l:])) + i*size
+      }
+      if offset+size > len(b) {
+        t.Fatalf("level %d of layer %d is past the end of the file", l, i)
+      }
+      out[i] = append(out[i], b[offset:offset+size])
+      offset += size
+      lw, lh = max1(lw/2), max1(lh/2)
+    }
+  }
+  return out
+}
+
+// TestTextureRoundTrip saves pages with their mip chains to KTX2 and DDS
+// files, checks each level against Atlas.MipChain, and loads the pages back,
+// exactly for uncompressed formats and within the error CompressionErrors
+// reports for block compressed ones.
+func TestTextureRoundTrip(t *testing.T) {
+  dir := t.TempDir()
+  for _, channels := range []bool{false, true} {
+    atlas := textureTestAtlas(t, channels)
+    formats := []TextureFormat{TextureR8, TextureR16, TextureRGBA8, TextureBC4, TextureEACR11}
+    if channels {
+      formats = []TextureFormat{TextureRG8, TextureRGBA8, TextureBC5}
+    }
+    for _, format := range formats {
+      var bound float32
+      if format.blockSize() > 0 {
+        errs, err := atlas.CompressionErrors(format)
+        if err != nil {
+          t.Fatal(err)
+        }
+        for _, e := range errs {
+          bound = float32(math.Max(float64(bound), float64(e.Max)))
+        }
+      }
+      for _, ext := range []string{".ktx2", ".dds"} {
+        if ext == ".dds" && format.dxgiFormat() == 0 {
+          continue
+        }
+        for _, array := range []bool{false, true} {
+          name := fmt.Sprintf("format %d, %s, array %v, channels %v", format, ext, array, channels)
+          base := filepath.Join(dir, fmt.Sprintf("atlas-%d-%v-%v", format, array, channels))
+          opts := TextureOptions{Format: format, Mipmaps: true, Array: array}
+          save, load := atlas.SaveKTX2Files, (*Atlas).LoadKTX2Files
+          if ext == ".dds" {
+            save, load = atlas.SaveDDSFiles, (*Atlas).LoadDDSFiles
+          }
+          if err := save(base, opts); err != nil {
+            t.Fatalf("%s: %v", name, err)
+          }
+          fileNames := []string{base + ext}
+          if !array {
+            fileNames = nil
+            for i := range atlas.Images {
+              fileNames = append(fileNames, fmt.Sprintf("%s-%d%s", base, i, ext))
+            }
+          }
+
+          // the saved mip chains
+          var saved [][][]byte
+          for _, fileName := range fileNames {
+            b, err := ioutil.ReadFile(fileName)
+            if err != nil {
+              t.Fatal(err)
+            }
+            layers := 1
+            if array {
+              layers = len(atlas.Images)
+            }
+            saved = append(saved, fileLevels(t, b, format, layers, ext == ".ktx2")...)
+          }
+          for i, img := range atlas.Images {
+            chain := atlas.mipChain(i, pagePlane(img, format.channels()))
+            if len(saved[i]) != atlas.MipLevels || len(chain) != atlas.MipLevels {
+              t.Fatalf("%s: page %d saved with %d levels, chain of %d, want %d", name, i, len(saved[i]), len(chain), atlas.MipLevels)
+            }
+            for l, p := range chain {
+              if string(saved[i][l]) != string(format.encode(p)) {
+                t.Errorf("%s: page %d level %d differs from its mip chain", name, i, l)
+              }
+            }
+          }
+
+          loaded := &Atlas{Format: atlas.Format, Effects: atlas.Effects}
+          if err := load(loaded, fileNames); err != nil {
+            t.Fatalf("%s: %v", name, err)
+          }
+          if len(loaded.Images) != len(atlas.Images) {
+            t.Fatalf("%s: loaded %d pages, not %d", name, len(loaded.Images), len(atlas.Images))
+          }
+          n := format.channels()
+          for i, img := range atlas.Images {
+            if loaded.Images[i].Bounds() != img.Bounds() {
+              t.Fatalf("%s: page %d is %v, not %v", name, i, loaded.Images[i].Bounds(), img.Bounds())
+            }
+            // loaded pages are 8 bits per channel, truncated
+            want, got := pagePlane(img, n), pagePlane(loaded.Images[i], n)
+            var worst float32
+            for k, v := range want.pix {
+              d := float32(math.Abs(float64(v)-float64(got.pix[k]))) / 0xFFFF
+              worst = float32(math.Max(float64(worst), float64(d)))
+            }
+            if worst > bound+1.0/255 || (bound == 0 && worst != 0) {
+              t.Errorf("%s: page %d is off by up to %v, more than %v", name, i, worst, bound)
+            }
+          }
+        }
+      }
+    }
+  }
+}
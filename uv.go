@@ -0,0 +1,110 @@
+package ratlas
+
+import (
+  "fmt"
+  "image"
+)
+
+// UVMode is how item Percent fields map to the texels of glyph images.
+type UVMode int
+
+const (
+  // UVTexelEdges spans the outer edges of glyph texels, so that a quad of
+  // the item's size maps texels to pixels one to one; bilinear sampling at
+  // the edges blends in half a texel of the gutter.
+  UVTexelEdges UVMode = iota
+  
+  // UVHalfTexelInset spans the centers of the edge texels, so bilinear
+  // sampling never reaches outside the glyph's image even without Spacing,
+  // at the cost of half a texel of it on each side.
+  UVHalfTexelInset
+)
+
+// UVOrigin is the corner of atlas pages that UV coordinates start from.
+type UVOrigin int
+
+const (
+  // UVTopLeft starts V at the top row, as pages are stored and uploaded.
+  UVTopLeft UVOrigin = iota
+  
+  // UVBottomLeft starts V at the bottom row, as OpenGL texture coordinates
+  // do for images uploaded bottom row first.
+  UVBottomLeft
+)
+
+// UVUnits are the units of UV coordinates.
+type UVUnits int
+
+const (
+  // UVNormalized runs UVs from 0 to 1 across pages.
+  UVNormalized UVUnits = iota
+  
+  // UVPixels counts UVs in texels, as for texelFetch and rectangle textures.
+  UVPixels
+)
+
+// inset returns how far in texels the mode's UVs lie inside glyph texels.
+func (mode UVMode) inset() float32 {
+  if mode == UVHalfTexelInset {
+    return 0.5
+  }
+  return 0
+}
+
+// setPercents sets the Percent fields, UVInset and UVs of a packed item on a
+// w by h page, in the atlas' UVMode and UV convention.
+func (atlas *Atlas) setPercents(atlasItem *AtlasItem, w, h int) {
+  inset := atlas.UVMode.inset()
+  atlasItem.UVInset = inset
+  atlasItem.PercentPosX = (float32(atlasItem.Node.X) + inset) / float32(w)
+  atlasItem.PercentPosY = (float32(atlasItem.Node.Y) + inset) / float32(h)
+  atlasItem.PercentWidth = (float32(atlasItem.Width) - 2*inset) / float32(w)
+  atlasItem.PercentHeight = (float32(atlasItem.Height) - 2*inset) / float32(h)
+  atlasItem.U0, atlasItem.V0, atlasItem.U1, atlasItem.V1 = uvRect(atlasItem, atlas.UVOrigin, atlas.UVUnits, image.Pt(w, h))
+}
+
+// uvRect returns the UVs of an item's top left and bottom right corners on a
+// page of a size, from its Percent fields.
+func uvRect(atlasItem *AtlasItem, origin UVOrigin, units UVUnits, size image.Point) (u0, v0, u1, v1 float32) {
+  u0, v0 = atlasItem.PercentPosX, atlasItem.PercentPosY
+  u1, v1 = u0+atlasItem.PercentWidth, v0+atlasItem.PercentHeight
+  if origin == UVBottomLeft {
+    v0, v1 = 1-v0, 1-v1
+  }
+  if units == UVPixels {
+    w, h := float32(size.X), float32(size.Y)
+    u0, v0, u1, v1 = u0*w, v0*h, u1*w, v1*h
+  }
+  return u0, v0, u1, v1
+}
+
+// UVRect returns the UVs of an item's top left and bottom right corners in a
+// convention other than the atlas', such as for a second renderer. Pixel
+// units need the item's page loaded, and are false without it.
+func (atlas *Atlas) UVRect(atlasItem *AtlasItem, origin UVOrigin, units UVUnits) (u0, v0, u1, v1 float32, ok bool) {
+  var size image.Point
+  if units == UVPixels {
+    if atlasItem.ImageIndex >= len(atlas.Images) {
+      return 0, 0, 0, 0, false
+    }
+    size = atlas.Images[atlasItem.ImageIndex].Bounds().Size()
+  }
+  u0, v0, u1, v1 = uvRect(atlasItem, origin, units, size)
+  return u0, v0, u1, v1, true
+}
+
+// SetUVConvention changes the convention of item UVs, and of Glyph quads made
+// from them. Pixel units need the pages loaded.
+func (atlas *Atlas) SetUVConvention(origin UVOrigin, units UVUnits) error {
+  items := atlas.allItems()
+  for _, atlasItem := range items {
+    if units == UVPixels && atlasItem.ImageIndex >= len(atlas.Images) {
+      return fmt.Errorf("ratlas: page %d isn't loaded", atlasItem.ImageIndex)
+    }
+  }
+  atlas.UVOrigin, atlas.UVUnits = origin, units
+  for _, atlasItem := range items {
+    atlasItem.U0, atlasItem.V0, atlasItem.U1, atlasItem.V1, _ = atlas.UVRect(atlasItem, origin, units)
+  }
+  return nil
+}
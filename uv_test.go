@@ -0,0 +1,136 @@
+package ratlas
+
+import (
+  "math"
+  "testing"
+)
+
+// uvTestAtlas returns an atlas of 128x128 pages in a UV convention.
+func uvTestAtlas(t *testing.T, origin UVOrigin, units UVUnits) *Atlas {
+  data := testFont(t)
+  atlas := NewWithOptions(&data, []rune("AgQ|."), Options{FontPt: 24, Width: 128, Height: 128, Pad: 2, UVMode: UVHalfTexelInset, UVOrigin: origin, UVUnits: units})
+  return &atlas
+}
+
+type uvConvention struct {
+  origin UVOrigin
+  units  UVUnits
+}
+
+var uvConventions = []uvConvention{
+  {UVTopLeft, UVNormalized},
+  {UVBottomLeft, UVNormalized},
+  {UVTopLeft, UVPixels},
+  {UVBottomLeft, UVPixels},
+}
+
+// wantUVs returns the UVs of an item in a convention, from its Percent
+// fields, on pages of size w by h.
+func wantUVs(item *AtlasItem, c uvConvention, w, h float32) [4]float32 {
+  u0, v0 := item.PercentPosX, item.PercentPosY
+  u1, v1 := u0+item.PercentWidth, v0+item.PercentHeight
+  if c.origin == UVBottomLeft {
+    v0, v1 = 1-v0, 1-v1
+  }
+  if c.units == UVPixels {
+    return [4]float32{u0 * w, v0 * h, u1 * w, v1 * h}
+  }
+  return [4]float32{u0, v0, u1, v1}
+}
+
+func uvs(item *AtlasItem) [4]float32 {
+  return [4]float32{item.U0, item.V0, item.U1, item.V1}
+}
+
+// TestUVConvention checks item UVs against their Percent fields in each
+// convention, as built and as set by SetUVConvention.
+func TestUVConvention(t *testing.T) {
+  for _, c := range uvConventions {
+    atlas := uvTestAtlas(t, c.origin, c.units)
+    for r, item := range atlas.Items {
+      if got, want := uvs(item), wantUVs(item, c, 128, 128); got != want {
+        t.Errorf("%+v: %q has UVs %v, want %v", c, r, got, want)
+      }
+      if c.origin == UVBottomLeft && item.V0 <= item.V1 || c.origin == UVTopLeft && item.V0 >= item.V1 {
+        t.Errorf("%+v: %q has V0 %v and V1 %v", c, r, item.V0, item.V1)
+      }
+    }
+  }
+
+  // from each convention to each other and back
+  for _, from := range uvConventions {
+    atlas := uvTestAtlas(t, from.origin, from.units)
+    built := make(map[rune][4]float32)
+    for r, item := range atlas.Items {
+      built[r] = uvs(item)
+    }
+    for _, to := range uvConventions {
+      if err := atlas.SetUVConvention(to.origin, to.units); err != nil {
+        t.Fatal(err)
+      }
+      if atlas.UVOrigin != to.origin || atlas.UVUnits != to.units {
+        t.Errorf("%+v to %+v: convention is %d, %d", from, to, atlas.UVOrigin, atlas.UVUnits)
+      }
+      for r, item := range atlas.Items {
+        u0, v0, u1, v1, ok := atlas.UVRect(item, to.origin, to.units)
+        if got, want := uvs(item), wantUVs(item, to, 128, 128); got != want || !ok || [4]float32{u0, v0, u1, v1} != want {
+          t.Errorf("%+v to %+v: %q has UVs %v, UVRect %v, want %v", from, to, r, got, [4]float32{u0, v0, u1, v1}, want)
+        }
+      }
+      if err := atlas.SetUVConvention(from.origin, from.units); err != nil {
+        t.Fatal(err)
+      }
+      for r, item := range atlas.Items {
+        if uvs(item) != built[r] {
+          t.Errorf("%+v to %+v and back: %q has UVs %v, want %v", from, to, r, uvs(item), built[r])
+        }
+      }
+    }
+  }
+
+  // pixels need the pages
+  atlas := uvTestAtlas(t, UVTopLeft, UVNormalized)
+  before := uvs(atlas.Items['A'])
+  atlas.Images = nil
+  if err := atlas.SetUVConvention(UVBottomLeft, UVPixels); err == nil {
+    t.Errorf("no error setting pixel UVs without pages")
+  }
+  if atlas.UVOrigin != UVTopLeft || atlas.UVUnits != UVNormalized || uvs(atlas.Items['A']) != before {
+    t.Errorf("failed SetUVConvention changed the atlas")
+  }
+  if _, _, _, _, ok := atlas.UVRect(atlas.Items['A'], UVTopLeft, UVPixels); ok {
+    t.Errorf("UVRect in pixels without pages")
+  }
+}
+
+// TestScaleNumbersUVs checks that ScaleNumbers keeps normalized UVs and
+// scales pixel UVs with the pages, in each convention.
+func TestScaleNumbersUVs(t *testing.T) {
+  const scale = 2
+  for _, c := range uvConventions {
+    atlas := uvTestAtlas(t, c.origin, c.units)
+    before := make(map[rune][4]float32)
+    for r, item := range atlas.Items {
+      before[r] = uvs(item)
+    }
+    atlas.ScaleNumbers(scale)
+    for r, item := range atlas.Items {
+      want := before[r]
+      if c.units == UVPixels {
+        for i := range want {
+          want[i] *= scale
+        }
+      }
+      got := uvs(item)
+      for i := range got {
+        if math.Abs(float64(got[i]-want[i])) > 1e-3 {
+          t.Errorf("%+v: %q has UVs %v after scaling, want %v", c, r, got, want)
+          break
+        }
+      }
+      if w := wantUVs(item, c, 128*scale, 128*scale); got != w {
+        t.Errorf("%+v: %q has UVs %v after scaling, and Percent fields giving %v", c, r, got, w)
+      }
+    }
+  }
+}